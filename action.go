@@ -0,0 +1,362 @@
+package zabbix
+
+import (
+	"strconv"
+)
+
+type (
+	EventSource       int
+	ActionStatus      int
+	EvalType          int
+	ConditionType     int
+	ConditionOperator int
+	OperationType     int
+)
+
+const (
+	EventSourceTrigger          EventSource = 0
+	EventSourceDiscovery        EventSource = 1
+	EventSourceAutoregistration EventSource = 2
+	EventSourceInternal         EventSource = 3
+	EventSourceService          EventSource = 4
+
+	ActionEnabled  ActionStatus = 0
+	ActionDisabled ActionStatus = 1
+
+	EvalAndOr  EvalType = 0
+	EvalAnd    EvalType = 1
+	EvalOr     EvalType = 2
+	EvalCustom EvalType = 3
+
+	ConditionHostGroup         ConditionType = 0
+	ConditionHost              ConditionType = 1
+	ConditionTrigger           ConditionType = 2
+	ConditionEventName         ConditionType = 3
+	ConditionTriggerSeverity   ConditionType = 4
+	ConditionTimePeriod        ConditionType = 6
+	ConditionHostIP            ConditionType = 7
+	ConditionDiscoveredService ConditionType = 8
+	ConditionDiscoveredPort    ConditionType = 9
+	ConditionDiscoveryStatus   ConditionType = 10
+	ConditionUptime            ConditionType = 11
+	ConditionReceivedValue     ConditionType = 12
+	ConditionHostTemplate      ConditionType = 13
+	ConditionEventAcknowledged ConditionType = 14 // operation conditions only
+	ConditionApplication       ConditionType = 15
+	ConditionProblemSuppressed ConditionType = 16
+	ConditionDiscoveryRule     ConditionType = 18
+	ConditionDiscoveryCheck    ConditionType = 19
+	ConditionProxy             ConditionType = 20
+	ConditionDiscoveryObject   ConditionType = 21
+	ConditionHostName          ConditionType = 22
+	ConditionEventType         ConditionType = 23
+	ConditionHostMetadata      ConditionType = 24
+	ConditionEventTag          ConditionType = 25
+	ConditionEventTagValue     ConditionType = 26
+	ConditionService           ConditionType = 27
+	ConditionServiceName       ConditionType = 28
+
+	OperatorEqual          ConditionOperator = 0
+	OperatorNotEqual       ConditionOperator = 1
+	OperatorContains       ConditionOperator = 2
+	OperatorNotContains    ConditionOperator = 3
+	OperatorIn             ConditionOperator = 4
+	OperatorGreaterOrEqual ConditionOperator = 5
+	OperatorLessOrEqual    ConditionOperator = 6
+	OperatorNotIn          ConditionOperator = 7
+	OperatorMatches        ConditionOperator = 8
+	OperatorNotMatches     ConditionOperator = 9
+	OperatorYes            ConditionOperator = 10
+	OperatorNo             ConditionOperator = 11
+
+	OperationSendMessage      OperationType = 0
+	OperationRemoteCommand    OperationType = 1
+	OperationAddHost          OperationType = 2
+	OperationRemoveHost       OperationType = 3
+	OperationAddToHostGroup   OperationType = 4
+	OperationRemoveFromGroup  OperationType = 5
+	OperationLinkTemplate     OperationType = 6
+	OperationUnlinkTemplate   OperationType = 7
+	OperationEnableHost       OperationType = 8
+	OperationDisableHost      OperationType = 9
+	OperationSetInventoryMode OperationType = 10
+	OperationNotifyRecovery   OperationType = 11 // recovery operations only
+	OperationNotifyUpdate     OperationType = 12 // update operations only
+	OperationAddHostTags      OperationType = 13
+	OperationRemoveHostTags   OperationType = 14
+)
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/action/object#action-filter-condition
+type ActionCondition struct {
+	ConditionId   string            `json:"conditionid,omitempty"`
+	ConditionType ConditionType     `json:"conditiontype"`
+	Operator      ConditionOperator `json:"operator"`
+	Value         string            `json:"value"`
+	Value2        string            `json:"value2,omitempty"`
+	FormulaId     string            `json:"formulaid,omitempty"`
+}
+
+type ActionConditions []ActionCondition
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/action/object#action-filter
+type ActionFilter struct {
+	EvalType   EvalType         `json:"evaltype"`
+	Formula    string           `json:"formula,omitempty"`
+	Conditions ActionConditions `json:"conditions"`
+}
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/action/object#action-operation-message
+type OpMessage struct {
+	DefaultMsg  int    `json:"default_msg"`
+	Subject     string `json:"subject,omitempty"`
+	Message     string `json:"message,omitempty"`
+	MediaTypeId string `json:"mediatypeid,omitempty"`
+}
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/action/object#action-operation-condition
+type OpCondition struct {
+	ConditionType ConditionType     `json:"conditiontype"`
+	Operator      ConditionOperator `json:"operator"`
+	Value         string            `json:"value"`
+}
+
+type TemplateId struct {
+	TemplateId string `json:"templateid"`
+}
+
+type TemplateIds []TemplateId
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/action/object#action-operation
+//
+// EscPeriod is in seconds, 0 means default action escalation period. OpCommand fields differ
+// between versions (scriptid since Zabbix 5.4) and are kept as is.
+type ActionOperation struct {
+	OperationId     string        `json:"operationid,omitempty"`
+	OperationType   OperationType `json:"operationtype"`
+	EscPeriod       int           `json:"esc_period,omitempty"`
+	EscStepFrom     int           `json:"esc_step_from,omitempty"`
+	EscStepTo       int           `json:"esc_step_to,omitempty"`
+	EvalType        EvalType      `json:"evaltype,omitempty"`
+	OpConditions    []OpCondition `json:"opconditions,omitempty"`
+	OpMessage       *OpMessage    `json:"opmessage,omitempty"`
+	OpMessageGroups UserGroupIds  `json:"opmessage_grp,omitempty"`
+	OpMessageUsers  UserIds       `json:"opmessage_usr,omitempty"`
+	OpCommand       Params        `json:"opcommand,omitempty"`
+	OpCommandGroups HostGroupIds  `json:"opcommand_grp,omitempty"`
+	OpCommandHosts  HostIds       `json:"opcommand_hst,omitempty"`
+	OpGroups        HostGroupIds  `json:"opgroup,omitempty"`
+	OpTemplates     TemplateIds   `json:"optemplate,omitempty"`
+}
+
+type ActionOperations []ActionOperation
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/action/object
+//
+// EscPeriod is in seconds. RecoveryOperations are supported since Zabbix 3.2, UpdateOperations since 3.4
+// (sent as acknowledge_operations before 5.0). PauseSuppressed and NotifyIfCanceled are supported since 4.0 and 6.0.
+type Action struct {
+	ActionId         string       `json:"actionid,omitempty"`
+	Name             string       `json:"name"`
+	EventSource      EventSource  `json:"eventsource"`
+	Status           ActionStatus `json:"status"`
+	EscPeriod        int          `json:"esc_period,omitempty"`
+	PauseSuppressed  int          `json:"pause_suppressed,omitempty"`
+	NotifyIfCanceled int          `json:"notify_if_canceled,omitempty"`
+
+	// Filled by ActionsGet
+	Filter             *ActionFilter    `json:"filter,omitempty"`
+	Operations         ActionOperations `json:"operations,omitempty"`
+	RecoveryOperations ActionOperations `json:"recovery_operations,omitempty"`
+	UpdateOperations   ActionOperations `json:"update_operations,omitempty"`
+}
+
+type Actions []Action
+
+// Wrapper for action.get: https://www.zabbix.com/documentation/current/manual/api/reference/action/get
+// Filter and all kinds of operations supported by server are selected by default.
+func (api *API) ActionsGet(params Params) (res Actions, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	selects := []string{"selectFilter", "selectOperations"}
+	if v.AtLeast(3, 2) {
+		selects = append(selects, "selectRecoveryOperations")
+	}
+	switch {
+	case v.AtLeast(5, 0):
+		selects = append(selects, "selectUpdateOperations")
+	case v.AtLeast(3, 4):
+		selects = append(selects, "selectAcknowledgeOperations")
+	}
+	for _, s := range selects {
+		if _, present := params[s]; !present {
+			params[s] = "extend"
+		}
+	}
+	response, err := api.CallWithError("action.get", params)
+	if err != nil {
+		return
+	}
+
+	maps := response.Result.([]interface{})
+	for _, m := range maps {
+		m := m.(map[string]interface{})
+		if ops, present := m["acknowledge_operations"]; present {
+			m["update_operations"] = ops
+		}
+		normalizeTimeUnits(m, map[string]int{"esc_period": 1})
+		for _, f := range []string{"operations", "recovery_operations", "update_operations"} {
+			ops, _ := m[f].([]interface{})
+			for _, op := range ops {
+				normalizeTimeUnits(op.(map[string]interface{}), map[string]int{"esc_period": 1})
+			}
+		}
+	}
+	mapsToStructs(maps, &res)
+	return
+}
+
+// Gets action by Id only if there is exactly 1 matching action.
+func (api *API) ActionGetById(id string) (res *Action, err error) {
+	actions, err := api.ActionsGet(Params{"actionids": id})
+	if err != nil {
+		return
+	}
+
+	if len(actions) == 1 {
+		res = &actions[0]
+	} else {
+		e := ExpectedOneResult(len(actions))
+		err = &e
+	}
+	return
+}
+
+// Gets action by name only if there is exactly 1 matching action.
+func (api *API) ActionGetByName(name string) (res *Action, err error) {
+	actions, err := api.ActionsGet(Params{"filter": map[string]string{"name": name}})
+	if err != nil {
+		return
+	}
+
+	if len(actions) == 1 {
+		res = &actions[0]
+	} else {
+		e := ExpectedOneResult(len(actions))
+		err = &e
+	}
+	return
+}
+
+// Converts actions to payload for action.create and action.update according to server version.
+func (api *API) actionsPayload(actions Actions) (res []map[string]interface{}, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	res, err = toMaps(actions)
+	if err != nil {
+		return
+	}
+
+	for i, action := range actions {
+		m := res[i]
+		switch {
+		case len(action.RecoveryOperations) > 0 && !v.AtLeast(3, 2):
+			err = &UnsupportedError{"Action RecoveryOperations", v}
+		case len(action.UpdateOperations) > 0 && !v.AtLeast(3, 4):
+			err = &UnsupportedError{"Action UpdateOperations", v}
+		case action.PauseSuppressed != 0 && !v.AtLeast(4, 0):
+			err = &UnsupportedError{"Action PauseSuppressed", v}
+		case action.NotifyIfCanceled != 0 && !v.AtLeast(6, 0):
+			err = &UnsupportedError{"Action NotifyIfCanceled", v}
+		}
+		if err != nil {
+			return
+		}
+
+		if action.EscPeriod != 0 {
+			m["esc_period"] = strconv.Itoa(action.EscPeriod)
+		}
+		for f, ops := range map[string]ActionOperations{"operations": action.Operations,
+			"recovery_operations": action.RecoveryOperations, "update_operations": action.UpdateOperations} {
+			maps, _ := m[f].([]interface{})
+			for j, op := range maps {
+				if ops[j].EscPeriod != 0 {
+					op.(map[string]interface{})["esc_period"] = strconv.Itoa(ops[j].EscPeriod)
+				}
+			}
+		}
+		if ops, present := m["update_operations"]; present && !v.AtLeast(5, 0) {
+			m["acknowledge_operations"] = ops
+			delete(m, "update_operations")
+		}
+	}
+	return
+}
+
+// Wrapper for action.create: https://www.zabbix.com/documentation/current/manual/api/reference/action/create
+func (api *API) ActionsCreate(actions Actions) (err error) {
+	payload, err := api.actionsPayload(actions)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("action.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	actionids := result["actionids"].([]interface{})
+	for i, id := range actionids {
+		actions[i].ActionId = id.(string)
+	}
+	return
+}
+
+// Wrapper for action.update: https://www.zabbix.com/documentation/current/manual/api/reference/action/update
+// Filter and operations are replaced if set.
+func (api *API) ActionsUpdate(actions Actions) (err error) {
+	payload, err := api.actionsPayload(actions)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("action.update", payload)
+	return
+}
+
+// Wrapper for action.delete: https://www.zabbix.com/documentation/current/manual/api/reference/action/delete
+// Cleans ActionId in all actions elements if call succeed.
+func (api *API) ActionsDelete(actions Actions) (err error) {
+	ids := make([]string, len(actions))
+	for i, action := range actions {
+		ids[i] = action.ActionId
+	}
+
+	err = api.ActionsDeleteByIds(ids)
+	if err == nil {
+		for i := range actions {
+			actions[i].ActionId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for action.delete: https://www.zabbix.com/documentation/current/manual/api/reference/action/delete
+func (api *API) ActionsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("action.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	actionids := result["actionids"].([]interface{})
+	if len(ids) != len(actionids) {
+		err = &ExpectedMore{len(ids), len(actionids)}
+	}
+	return
+}
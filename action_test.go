@@ -0,0 +1,92 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestActions(t *testing.T) {
+	version := "4.2.0"
+	var payload []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "action.create":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			return map[string]interface{}{"actionids": []string{"3"}}, nil
+		case "action.get":
+			return []map[string]interface{}{{
+				"actionid": "3", "name": "Notify ops", "eventsource": "0", "status": "0", "esc_period": "1h",
+				"filter": map[string]interface{}{"evaltype": "0", "formula": "", "conditions": []map[string]string{
+					{"conditiontype": "4", "operator": "5", "value": "3", "value2": "", "formulaid": "A"},
+				}},
+				"operations": []map[string]interface{}{{
+					"operationid": "8", "operationtype": "0", "esc_period": "0", "esc_step_from": "1", "esc_step_to": "2",
+					"evaltype": "0", "opconditions": []interface{}{},
+					"opmessage":     map[string]string{"default_msg": "1", "subject": "", "message": "", "mediatypeid": "1"},
+					"opmessage_grp": []map[string]string{{"usrgrpid": "7"}},
+				}, {
+					"operationid": "9", "operationtype": "1", "esc_period": "5m", "opmessage": []interface{}{},
+					"opcommand_hst": []map[string]string{{"hostid": "10"}},
+				}},
+				"acknowledge_operations": []map[string]interface{}{{"operationid": "10", "operationtype": "12"}},
+			}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	expected := Action{
+		Name:      "Notify ops",
+		EscPeriod: 3600,
+		Filter: &ActionFilter{Conditions: ActionConditions{
+			{ConditionType: ConditionTriggerSeverity, Operator: OperatorGreaterOrEqual, Value: "3", FormulaId: "A"},
+		}},
+		Operations: ActionOperations{{
+			OperationType:   OperationSendMessage,
+			EscStepFrom:     1,
+			EscStepTo:       2,
+			OpMessage:       &OpMessage{DefaultMsg: 1, MediaTypeId: "1"},
+			OpMessageGroups: UserGroupIds{{"7"}},
+		}, {
+			OperationType:  OperationRemoteCommand,
+			EscPeriod:      300,
+			OpCommandHosts: HostIds{{"10"}},
+		}},
+		UpdateOperations: ActionOperations{{OperationType: OperationNotifyUpdate}},
+	}
+	actions := Actions{expected}
+	if err := api.ActionsCreate(actions); err != nil {
+		t.Fatal(err)
+	}
+	m := payload[0]
+	if m["esc_period"] != "3600" || m["acknowledge_operations"] == nil || m["update_operations"] != nil {
+		t.Errorf("Bad payload: %#v", m)
+	}
+	if op := m["operations"].([]interface{})[1].(map[string]interface{}); op["esc_period"] != "300" {
+		t.Errorf("Bad operation payload: %#v", op)
+	}
+
+	action, err := api.ActionGetById("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected.ActionId = "3"
+	expected.Operations[0].OperationId = "8"
+	expected.Operations[1].OperationId = "9"
+	expected.UpdateOperations[0].OperationId = "10"
+	if !reflect.DeepEqual(*action, expected) {
+		t.Errorf("Actions are not equal:\n%#v\n%#v", *action, expected)
+	}
+
+	version = "3.2.0"
+	api = NewAPI(srv.URL)
+	if err = api.ActionsCreate(actions); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Active check sent to agent in response to "active checks" request.
+type ActiveCheck struct {
+	Key         string `json:"key"`
+	Delay       int    `json:"delay"`
+	LastLogSize int64  `json:"lastlogsize"`
+	MTime       int64  `json:"mtime"`
+}
+
+type ActiveChecks []ActiveCheck
+
+// Value received from agent in "agent data" (or from zabbix_sender in "sender data") request.
+// State is 1 if item became not supported; Value contains error message then.
+type AgentValue struct {
+	Host        string `json:"host"`
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	State       int    `json:"state,omitempty"`
+	Clock       int64  `json:"clock,omitempty"`
+	Ns          int    `json:"ns,omitempty"`
+	Id          int64  `json:"id,omitempty"`
+	LastLogSize int64  `json:"lastlogsize,omitempty"`
+	MTime       int64  `json:"mtime,omitempty"`
+}
+
+type AgentValues []AgentValue
+
+// ActiveChecksServer is an in-process implementation of server side of Zabbix active checks protocol
+// for testing ZabbixAgentActive items without real server:
+// https://www.zabbix.com/documentation/current/manual/appendix/protocols/zabbix_agent
+//
+// It answers "active checks" requests with checks set by SetItems or SetChecks and stores values
+// from "agent data" and "sender data" requests. Configure agent's ServerActive with Addr.
+type ActiveChecksServer struct {
+	Addr string
+
+	l       net.Listener
+	m       sync.Mutex
+	checks  map[string]ActiveChecks
+	values  AgentValues
+	changed chan struct{}
+	wg      sync.WaitGroup
+}
+
+type activeChecksRequest struct {
+	Request string      `json:"request"`
+	Host    string      `json:"host"`
+	Data    AgentValues `json:"data"`
+}
+
+// Starts server listening on random port of loopback interface.
+func NewActiveChecksServer() (s *ActiveChecksServer, err error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return
+	}
+	s = &ActiveChecksServer{
+		Addr:    l.Addr().String(),
+		l:       l,
+		checks:  make(map[string]ActiveChecks),
+		changed: make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return
+}
+
+// Sets active checks for host from its ZabbixAgentActive items; other items are ignored.
+// Returns *ValidationError if item delay is not a simple time unit like "30s", as server resolves
+// user macros and flexible intervals itself.
+func (s *ActiveChecksServer) SetItems(host string, items Items) (err error) {
+	checks := make(ActiveChecks, 0, len(items))
+	for _, item := range items {
+		if item.Type != ZabbixAgentActive {
+			continue
+		}
+		delay, ok := parseTimeUnit(item.Delay)
+		if !ok {
+			return &ValidationError{"delay", fmt.Sprintf("%q of item %s can't be converted to seconds", item.Delay, item.Key)}
+		}
+		checks = append(checks, ActiveCheck{Key: item.Key, Delay: delay})
+	}
+	s.SetChecks(host, checks)
+	return
+}
+
+// Sets active checks for host. Requests for hosts without checks are answered with failure, as for unknown host.
+func (s *ActiveChecksServer) SetChecks(host string, checks ActiveChecks) {
+	s.m.Lock()
+	s.checks[host] = checks
+	s.m.Unlock()
+}
+
+// Returns all values received so far.
+func (s *ActiveChecksServer) Values() (res AgentValues) {
+	s.m.Lock()
+	res = append(res, s.values...)
+	s.m.Unlock()
+	return
+}
+
+// Waits until at least n values are received and returns all of them.
+// Returns received values and error on timeout.
+func (s *ActiveChecksServer) WaitValues(n int, timeout time.Duration) (res AgentValues, err error) {
+	deadline := time.After(timeout)
+	for {
+		s.m.Lock()
+		res = append(AgentValues{}, s.values...)
+		changed := s.changed
+		s.m.Unlock()
+		if len(res) >= n {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-deadline:
+			err = fmt.Errorf("Received %d values, expected %d.", len(res), n)
+			return
+		}
+	}
+}
+
+// Stops server and waits for active connections to be handled.
+func (s *ActiveChecksServer) Close() (err error) {
+	err = s.l.Close()
+	s.wg.Wait()
+	return
+}
+
+func (s *ActiveChecksServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(10 * time.Second))
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *ActiveChecksServer) handle(conn net.Conn) {
+	data, err := readPacket(conn)
+	if err != nil {
+		return
+	}
+
+	var req activeChecksRequest
+	var res map[string]interface{}
+	if err = json.Unmarshal(data, &req); err != nil {
+		res = map[string]interface{}{"response": "failed", "info": "cannot parse request: " + err.Error()}
+	} else {
+		res = s.respond(&req)
+	}
+
+	b, err := json.Marshal(res)
+	if err == nil {
+		writePacket(conn, b)
+	}
+}
+
+func (s *ActiveChecksServer) respond(req *activeChecksRequest) map[string]interface{} {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	switch req.Request {
+	case "active checks":
+		checks, present := s.checks[req.Host]
+		if !present {
+			return map[string]interface{}{"response": "failed", "info": fmt.Sprintf("host [%s] not found", req.Host)}
+		}
+		return map[string]interface{}{"response": "success", "data": checks, "regexp": []interface{}{}}
+
+	case "agent data", "sender data":
+		s.values = append(s.values, req.Data...)
+		close(s.changed)
+		s.changed = make(chan struct{})
+		info := fmt.Sprintf("processed: %d; failed: 0; total: %d; seconds spent: 0.000001", len(req.Data), len(req.Data))
+		return map[string]interface{}{"response": "success", "info": info}
+	}
+
+	return map[string]interface{}{"response": "failed", "info": fmt.Sprintf("unsupported request %q", req.Request)}
+}
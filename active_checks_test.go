@@ -0,0 +1,97 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Sends request to Zabbix server (or proxy) as agent does and returns response.
+func zabbixRequest(t *testing.T, addr string, req interface{}) (res map[string]interface{}) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	data, _ := json.Marshal(req)
+	packet := append([]byte("ZBXD"), 1, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(packet[5:], uint32(len(data)))
+	if _, err = conn.Write(append(packet, data...)); err != nil {
+		t.Fatal(err)
+	}
+
+	header := make([]byte, 13)
+	if _, err = io.ReadFull(conn, header); err != nil {
+		t.Fatal(err)
+	}
+	data = make([]byte, binary.LittleEndian.Uint32(header[5:]))
+	if _, err = io.ReadFull(conn, data); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestActiveChecksServer(t *testing.T) {
+	s, err := NewActiveChecksServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	err = s.SetItems("host1", Items{
+		{Key: "system.cpu.load[all,avg1]", Type: ZabbixAgentActive, Delay: "30s"},
+		{Key: "agent.ping", Type: ZabbixAgent, Delay: "{$INTERVAL}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.SetItems("host2", Items{{Key: "agent.ping", Type: ZabbixAgentActive, Delay: "{$INTERVAL}"}}); !IsInvalidParams(err) {
+		t.Errorf("Expected invalid params error, got %v", err)
+	}
+
+	res := zabbixRequest(t, s.Addr, map[string]string{"request": "active checks", "host": "host1"})
+	expected := map[string]interface{}{
+		"response": "success",
+		"data":     []interface{}{map[string]interface{}{"key": "system.cpu.load[all,avg1]", "delay": float64(30), "lastlogsize": float64(0), "mtime": float64(0)}},
+		"regexp":   []interface{}{},
+	}
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("Bad response: %#v", res)
+	}
+
+	res = zabbixRequest(t, s.Addr, map[string]string{"request": "active checks", "host": "unknown"})
+	if res["response"] != "failed" {
+		t.Errorf("Bad response: %#v", res)
+	}
+
+	res = zabbixRequest(t, s.Addr, map[string]interface{}{"request": "agent data", "session": "1", "data": []map[string]interface{}{
+		{"host": "host1", "key": "system.cpu.load[all,avg1]", "value": "0.15", "clock": 1600000000, "ns": 1, "id": 1},
+		{"host": "host1", "key": "vfs.fs.size[/,bad]", "value": "Invalid second parameter.", "state": 1, "id": 2},
+	}})
+	if res["response"] != "success" {
+		t.Errorf("Bad response: %#v", res)
+	}
+	values, err := s.WaitValues(2, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[0].Value != "0.15" || values[0].Clock != 1600000000 || values[1].State != 1 {
+		t.Errorf("Bad values: %#v", values)
+	}
+	if len(s.Values()) != 2 {
+		t.Errorf("Bad values: %#v", s.Values())
+	}
+
+	if _, err = s.WaitValues(3, 50*time.Millisecond); err == nil {
+		t.Error("Expected timeout error")
+	}
+}
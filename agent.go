@@ -0,0 +1,96 @@
+package zabbix
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Default Zabbix agent port and timeout of passive checks.
+const (
+	DefaultAgentPort    = "10050"
+	DefaultAgentTimeout = 3 * time.Second
+)
+
+// AgentError is returned by AgentClient.Get when agent can't get value of item key:
+// key is not supported (ZBX_NOTSUPPORTED) or agent failed to process request (ZBX_ERROR).
+type AgentError struct {
+	Key     string
+	Code    string // ZBX_NOTSUPPORTED or ZBX_ERROR
+	Message string
+}
+
+func (e *AgentError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Key)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.Code, e.Key, e.Message)
+}
+
+// AgentClient is a client for Zabbix agent passive checks:
+// https://www.zabbix.com/documentation/current/manual/appendix/items/activepassive#passive-checks
+type AgentClient struct {
+	Address string        // host:port
+	Timeout time.Duration // for whole request, DefaultAgentTimeout if zero
+}
+
+// Returns client for Zabbix agent interface. IP or DNS name is used according to UseIP,
+// port defaults to DefaultAgentPort. Interface macros are not expanded.
+func NewAgentClient(iface HostInterface) (client *AgentClient, err error) {
+	if iface.Type != Agent {
+		err = &ValidationError{"interface type", fmt.Sprintf("expected Zabbix agent interface, got %d", iface.Type)}
+		return
+	}
+	host := iface.DNS
+	if iface.UseIP == 1 {
+		host = iface.IP
+	}
+	if host == "" {
+		err = &ValidationError{"interface address", "empty"}
+		return
+	}
+	port := iface.Port
+	if port == "" {
+		port = DefaultAgentPort
+	}
+	client = &AgentClient{Address: net.JoinHostPort(host, port)}
+	return
+}
+
+// Gets value of item key from agent. Returns *AgentError if key is not supported.
+func (client *AgentClient) Get(key string) (value string, err error) {
+	timeout := client.Timeout
+	if timeout == 0 {
+		timeout = DefaultAgentTimeout
+	}
+	conn, err := net.DialTimeout("tcp", client.Address, timeout)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	err = conn.SetDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return
+	}
+
+	err = writePacket(conn, []byte(key))
+	if err != nil {
+		return
+	}
+	data, err := readPacket(conn)
+	if err != nil {
+		return
+	}
+
+	for _, code := range []string{"ZBX_NOTSUPPORTED", "ZBX_ERROR"} {
+		if bytes.HasPrefix(data, []byte(code)) {
+			message := string(bytes.TrimPrefix(data, []byte(code)))
+			err = &AgentError{Key: key, Code: code, Message: strings.Trim(message, "\x00")}
+			return
+		}
+	}
+	value = strings.TrimRight(string(data), "\n")
+	return
+}
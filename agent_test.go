@@ -0,0 +1,110 @@
+package zabbix_test
+
+import (
+	. "."
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// Starts fake Zabbix agent which answers passive checks with given function.
+func newFakeAgent(t *testing.T, answer func(key string) string) (iface HostInterface, stop func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				header := make([]byte, 13)
+				if _, err := io.ReadFull(conn, header); err != nil {
+					return
+				}
+				key := make([]byte, binary.LittleEndian.Uint32(header[5:]))
+				if _, err := io.ReadFull(conn, key); err != nil {
+					return
+				}
+
+				// answer with compression if requested
+				data := []byte(answer(string(key)))
+				flags, reserved := byte(1), 0
+				if string(key) == "compressed" {
+					var buf bytes.Buffer
+					w := zlib.NewWriter(&buf)
+					w.Write(data)
+					w.Close()
+					flags, reserved, data = 3, len(data), buf.Bytes()
+				}
+				res := append([]byte("ZBXD"), flags, 0, 0, 0, 0, 0, 0, 0, 0)
+				binary.LittleEndian.PutUint32(res[5:], uint32(len(data)))
+				binary.LittleEndian.PutUint32(res[9:], uint32(reserved))
+				conn.Write(append(res, data...))
+			}()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	iface = HostInterface{IP: host, Port: port, Type: Agent, UseIP: 1, Main: 1}
+	return iface, func() { l.Close() }
+}
+
+func TestAgentClient(t *testing.T) {
+	iface, stop := newFakeAgent(t, func(key string) string {
+		switch key {
+		case "agent.ping":
+			return "1"
+		case "compressed":
+			return "compressed value"
+		case "slow":
+			time.Sleep(time.Second)
+			return "1"
+		}
+		return "ZBX_NOTSUPPORTED\x00Unsupported item key."
+	})
+	defer stop()
+
+	client, err := NewAgentClient(iface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Timeout = 200 * time.Millisecond
+
+	for key, expected := range map[string]string{"agent.ping": "1", "compressed": "compressed value"} {
+		value, err := client.Get(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != expected {
+			t.Errorf("%s: expected %q, got %q", key, expected, value)
+		}
+	}
+
+	_, err = client.Get("vfs.fs.size[/,bad]")
+	var agentErr *AgentError
+	if !errors.As(err, &agentErr) || agentErr.Code != "ZBX_NOTSUPPORTED" || agentErr.Message != "Unsupported item key." {
+		t.Errorf("Expected agent error, got %v", err)
+	}
+
+	_, err = client.Get("slow")
+	if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+		t.Errorf("Expected timeout, got %v", err)
+	}
+
+	if _, err = NewAgentClient(HostInterface{IP: "127.0.0.1", Type: SNMP, UseIP: 1}); !IsInvalidParams(err) {
+		t.Errorf("Expected invalid params error, got %v", err)
+	}
+	client, _ = NewAgentClient(HostInterface{DNS: "agent.local", Type: Agent})
+	if client.Address != "agent.local:10050" {
+		t.Errorf("Bad address: %s", client.Address)
+	}
+}
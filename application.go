@@ -5,6 +5,7 @@ import (
 )
 
 // https://www.zabbix.com/documentation/2.0/manual/appendix/api/application/definitions
+// Applications were removed in Zabbix 5.4; all wrappers return *UnsupportedError there.
 type Application struct {
 	ApplicationId string `json:"applicationid,omitempty"`
 	HostId        string `json:"hostid"`
@@ -14,11 +15,23 @@ type Application struct {
 
 type Applications []Application
 
+func (api *API) checkApplications() (err error) {
+	v, err := api.ServerVersion()
+	if err == nil && v.AtLeast(5, 4) {
+		err = &UnsupportedError{"Applications", v}
+	}
+	return
+}
+
 // Wrapper for application.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/application/get
 func (api *API) ApplicationsGet(params Params) (res Applications, err error) {
 	if _, present := params["output"]; !present {
 		params["output"] = "extend"
 	}
+	err = api.checkApplications()
+	if err != nil {
+		return
+	}
 	response, err := api.CallWithError("application.get", params)
 	if err != nil {
 		return
@@ -60,8 +73,28 @@ func (api *API) ApplicationGetByHostIdAndName(hostId, name string) (res *Applica
 	return
 }
 
+// Gets application by host Id and name, creating it if it doesn't exist.
+func (api *API) ApplicationEnsure(hostId, name string) (res *Application, err error) {
+	res, err = api.ApplicationGetByHostIdAndName(hostId, name)
+	if !IsNotFound(err) {
+		return
+	}
+
+	apps := Applications{{HostId: hostId, Name: name}}
+	err = api.ApplicationsCreate(apps)
+	if err != nil {
+		return nil, err
+	}
+	res = &apps[0]
+	return
+}
+
 // Wrapper for application.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/application/create
 func (api *API) ApplicationsCreate(apps Applications) (err error) {
+	err = api.checkApplications()
+	if err != nil {
+		return
+	}
 	response, err := api.CallWithError("application.create", apps)
 	if err != nil {
 		return
@@ -75,6 +108,16 @@ func (api *API) ApplicationsCreate(apps Applications) (err error) {
 	return
 }
 
+// Wrapper for application.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/application/update
+func (api *API) ApplicationsUpdate(apps Applications) (err error) {
+	err = api.checkApplications()
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("application.update", apps)
+	return
+}
+
 // Wrapper for application.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/application/delete
 // Cleans ApplicationId in all apps elements if call succeed.
 func (api *API) ApplicationsDelete(apps Applications) (err error) {
@@ -94,6 +137,10 @@ func (api *API) ApplicationsDelete(apps Applications) (err error) {
 
 // Wrapper for application.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/application/delete
 func (api *API) ApplicationsDeleteByIds(ids []string) (err error) {
+	err = api.checkApplications()
+	if err != nil {
+		return
+	}
 	response, err := api.CallWithError("application.delete", ids)
 	if err != nil {
 		return
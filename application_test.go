@@ -74,3 +74,31 @@ func TestApplications(t *testing.T) {
 
 	DeleteApplication(app, t)
 }
+
+func TestApplicationEnsure(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	app, err := api.ApplicationEnsure(host.HostId, "App for "+host.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.ApplicationId == "" {
+		t.Errorf("Id is empty: %#v", app)
+	}
+
+	app2, err := api.ApplicationEnsure(host.HostId, "App for "+host.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app2.ApplicationId != app.ApplicationId {
+		t.Errorf("Apps are not equal:\n%#v\n%#v", app, app2)
+	}
+
+	DeleteApplication(app, t)
+}
@@ -2,11 +2,16 @@ package zabbix_test
 
 import (
 	. "."
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"log"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
+	"sync"
 	"testing"
 	"time"
 )
@@ -25,10 +30,6 @@ func init() {
 		log.Fatal(err)
 	}
 	_host += "-testing"
-
-	if os.Getenv("TEST_ZABBIX_URL") == "" {
-		log.Fatal("Set environment variables TEST_ZABBIX_URL (and optionally TEST_ZABBIX_USER and TEST_ZABBIX_PASSWORD)")
-	}
 }
 
 func getHost() string {
@@ -41,11 +42,17 @@ func getAPI(t *testing.T) *API {
 	}
 
 	url, user, password := os.Getenv("TEST_ZABBIX_URL"), os.Getenv("TEST_ZABBIX_USER"), os.Getenv("TEST_ZABBIX_PASSWORD")
+	if url == "" {
+		if testing.Short() {
+			t.Skip("TEST_ZABBIX_URL is not set")
+		}
+		t.Fatal("Set environment variables TEST_ZABBIX_URL (and optionally TEST_ZABBIX_USER and TEST_ZABBIX_PASSWORD)")
+	}
 	_api = NewAPI(url)
 	_api.SetClient(http.DefaultClient)
 	v := os.Getenv("TEST_ZABBIX_VERBOSE")
 	if v != "" && v != "0" {
-		_api.Logger = log.New(os.Stderr, "[zabbix] ", 0)
+		_api.SetLogger(log.New(os.Stderr, "[zabbix] ", 0))
 	}
 
 	if user != "" {
@@ -61,6 +68,93 @@ func getAPI(t *testing.T) *API {
 	return _api
 }
 
+// fakeHandler returns result or API error for method and raw params.
+type fakeHandler func(method string, params json.RawMessage, auth string) (interface{}, *Error)
+
+// Starts local fake Zabbix API server. Used by tests which don't need real server.
+func newFakeServer(t *testing.T, handler fakeHandler) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		var req struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+			Auth   string          `json:"auth"`
+			Id     int32           `json:"id"`
+		}
+		if err = json.Unmarshal(b, &req); err != nil {
+			t.Error(err)
+			return
+		}
+
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.Id}
+		result, apiErr := handler(req.Method, req.Params, req.Auth)
+		if apiErr != nil {
+			res["error"] = apiErr
+		} else {
+			res["result"] = result
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+}
+
+func TestConcurrentUse(t *testing.T) {
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "user.login":
+			return fmt.Sprintf("token-%d", rand.Int()), nil
+		case "APIInfo.version":
+			return "2.0.8", nil
+		}
+		if auth == "" {
+			return nil, &Error{-32602, "Invalid params.", "Not authorised."}
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	if _, err := api.Login("user", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			if _, err := api.Login("user", "password"); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := api.HostsGet(Params{}); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			api.SetLogger(log.New(ioutil.Discard, "", 0))
+			api.SetClient(&http.Client{Timeout: time.Minute})
+		}()
+		go func() {
+			defer wg.Done()
+			if api.Auth() == "" {
+				t.Error("Auth is empty")
+			}
+			if _, err := api.Version(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func TestBadCalls(t *testing.T) {
 	api := getAPI(t)
 	res, err := api.Call("", nil)
@@ -79,7 +173,7 @@ func TestVersion(t *testing.T) {
 		t.Fatal(err)
 	}
 	t.Logf("Zabbix version %s", v)
-	if !regexp.MustCompile(`^\d\.\d\.\d$`).MatchString(v) {
+	if !regexp.MustCompile(`^\d+\.\d+\.\d+`).MatchString(v) {
 		t.Errorf("Unexpected version: %s", v)
 	}
 }
@@ -0,0 +1,149 @@
+package zabbix
+
+import (
+	"container/list"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores responses of read-only "*.get" API methods; use API.SetCache to enable it.
+// Responses are keyed by method, params and auth token, and expire after TTL.
+// When the same API performs any other method of object type (create, update, delete, massupdate, etc.),
+// cached responses for that object type are dropped. Changes made by other clients, and changes of
+// related objects (for example, items deleted together with host) are not tracked: use Invalidate or Clear.
+// Cache is safe for concurrent use by multiple goroutines and may be shared by several API objects.
+type Cache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	m           sync.Mutex
+	entries     map[string]*list.Element
+	lru         *list.List        // front is most recently used
+	generations map[string]uint64 // per object type, incremented on invalidation
+	cleared     uint64            // incremented on Clear
+	stats       CacheStats
+}
+
+// Cache hit/miss statistics.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 // entries removed because of size limit
+	Entries   int
+}
+
+type cacheEntry struct {
+	key     string
+	object  string
+	b       []byte
+	expires time.Time
+}
+
+// Creates new cache with given TTL and maximum number of entries (unlimited if zero);
+// least recently used entries are evicted when limit is reached.
+func NewCache(ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		ttl:         ttl,
+		maxEntries:  maxEntries,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+		generations: make(map[string]uint64),
+	}
+}
+
+// Returns object type and true for read-only method.
+func cacheObject(method string) (object string, readonly bool) {
+	method = strings.ToLower(method)
+	dot := strings.LastIndexByte(method, '.')
+	if dot < 0 {
+		return method, false
+	}
+	return method[:dot], method[dot+1:] == "get"
+}
+
+func cacheKey(method string, params interface{}, auth string) (key string, err error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	key = strings.ToLower(method) + "\x00" + auth + "\x00" + string(b)
+	return
+}
+
+// Returns cached response and true, or current generation of object type for put.
+func (c *Cache) get(key, object string) (b []byte, generation uint64, ok bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if e, present := c.entries[key]; present {
+		entry := e.Value.(*cacheEntry)
+		if time.Now().Before(entry.expires) {
+			c.lru.MoveToFront(e)
+			c.stats.Hits++
+			return entry.b, 0, true
+		}
+		c.remove(e)
+	}
+	c.stats.Misses++
+	return nil, c.generations[object] + c.cleared, false
+}
+
+// Stores response unless object type was invalidated since get.
+func (c *Cache) put(key, object string, b []byte, generation uint64) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.generations[object]+c.cleared != generation {
+		return
+	}
+	if e, present := c.entries[key]; present {
+		c.remove(e)
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key, object, b, time.Now().Add(c.ttl)})
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *Cache) remove(e *list.Element) {
+	c.lru.Remove(e)
+	delete(c.entries, e.Value.(*cacheEntry).key)
+}
+
+// Drops cached responses for object type like "host" or "hostgroup".
+func (c *Cache) Invalidate(object string) {
+	object = strings.ToLower(object)
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.generations[object]++
+	for e := c.lru.Front(); e != nil; {
+		next := e.Next()
+		if e.Value.(*cacheEntry).object == object {
+			c.remove(e)
+		}
+		e = next
+	}
+}
+
+// Drops all cached responses.
+func (c *Cache) Clear() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.cleared++
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// Returns cache statistics.
+func (c *Cache) Stats() (stats CacheStats) {
+	c.m.Lock()
+	stats = c.stats
+	stats.Entries = c.lru.Len()
+	c.m.Unlock()
+	return
+}
@@ -0,0 +1,114 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	calls := make(map[string]int)
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		calls[method]++
+		switch method {
+		case "APIInfo.version":
+			return "5.0.0", nil
+		case "hostgroup.get":
+			return []map[string]string{{"groupid": "1", "name": "Group", "internal": "0"}}, nil
+		case "hostgroup.create":
+			return map[string]interface{}{"groupids": []string{"2"}}, nil
+		case "host.get":
+			return []map[string]string{{"hostid": "10", "host": "h", "proxy_hostid": "0"}}, nil
+		case "application.get":
+			return []interface{}{}, &Error{-32500, "Application error.", "No permissions."}
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	cache := NewCache(time.Hour, 2)
+	api.SetCache(cache)
+
+	for i := 0; i < 3; i++ {
+		groups, err := api.HostGroupsGet(Params{"groupids": "1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(groups) != 1 || groups[0].Name != "Group" {
+			t.Errorf("Bad groups: %#v", groups)
+		}
+
+		// HostsGet modifies result maps, so each call should get fresh copy
+		hosts, err := api.HostsGetByHostGroupIds([]string{"1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hosts) != 1 || hosts[0].ProxyId != "" {
+			t.Errorf("Bad hosts: %#v", hosts)
+		}
+	}
+	if calls["hostgroup.get"] != 1 || calls["host.get"] != 1 {
+		t.Errorf("Bad calls: %v", calls)
+	}
+	stats := cache.Stats()
+	if stats.Hits != 4 || stats.Misses != 2 || stats.Entries != 2 {
+		t.Errorf("Bad stats: %#v", stats)
+	}
+
+	// params are part of key
+	if _, err := api.HostGroupsGet(Params{"groupids": "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if calls["hostgroup.get"] != 2 || cache.Stats().Evictions != 1 || cache.Stats().Entries != 2 {
+		t.Errorf("Bad calls or stats: %v %#v", calls, cache.Stats())
+	}
+
+	// write invalidates cache for object type
+	if err := api.HostGroupsCreate(HostGroups{{Name: "New"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.HostGroupsGet(Params{"groupids": "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if calls["hostgroup.get"] != 3 {
+		t.Errorf("Bad calls: %v", calls)
+	}
+
+	// errors are not cached
+	for i := 0; i < 2; i++ {
+		if _, err := api.ApplicationsGet(Params{}); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if calls["application.get"] != 2 {
+		t.Errorf("Bad calls: %v", calls)
+	}
+
+	// auth is part of key
+	api.SetAuth("other")
+	if _, err := api.HostGroupsGet(Params{"groupids": "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if calls["hostgroup.get"] != 4 {
+		t.Errorf("Bad calls: %v", calls)
+	}
+
+	cache.Clear()
+	if cache.Stats().Entries != 0 {
+		t.Errorf("Bad stats: %#v", cache.Stats())
+	}
+
+	cache = NewCache(time.Nanosecond, 0)
+	api.SetCache(cache)
+	for i := 0; i < 2; i++ {
+		time.Sleep(time.Millisecond)
+		if _, err := api.HostGroupsGet(Params{"groupids": "1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls["hostgroup.get"] != 6 || cache.Stats().Hits != 0 {
+		t.Errorf("Bad calls or stats: %v %#v", calls, cache.Stats())
+	}
+}
@@ -0,0 +1,151 @@
+package zabbix
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeletePlan lists objects which would be deleted together with host groups or hosts.
+// It is returned by DeletePlanForHostGroups and DeletePlanForHosts for review and executed by DeletePlanExecute.
+//
+// Hosts include hosts which would be left without groups, as Zabbix refuses to delete host groups otherwise.
+// Items, Applications and Triggers are deleted by Zabbix together with hosts; they are listed for review only.
+// Templates in host groups (before Zabbix 6.2) are not considered.
+type DeletePlan struct {
+	HostGroups   HostGroups
+	Hosts        Hosts
+	Items        Items
+	Applications Applications // always empty since Zabbix 5.4
+	Triggers     Triggers
+}
+
+// Returns human-readable report of objects to delete.
+func (plan *DeletePlan) String() string {
+	groups := make([]string, len(plan.HostGroups))
+	for i, group := range plan.HostGroups {
+		groups[i] = group.Name
+	}
+	hosts := make([]string, len(plan.Hosts))
+	for i, host := range plan.Hosts {
+		hosts[i] = host.Host
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Host groups (%d): %s\n", len(groups), strings.Join(groups, ", "))
+	fmt.Fprintf(&b, "Hosts (%d): %s\n", len(hosts), strings.Join(hosts, ", "))
+	fmt.Fprintf(&b, "Items: %d\n", len(plan.Items))
+	fmt.Fprintf(&b, "Applications: %d\n", len(plan.Applications))
+	fmt.Fprintf(&b, "Triggers: %d\n", len(plan.Triggers))
+	return b.String()
+}
+
+// Returns plan for deleting host groups with given Ids: hosts which are only in those groups,
+// and their items, applications and triggers. Returns *ValidationError for internal host groups
+// and ExpectedMore if some host groups are not found.
+func (api *API) DeletePlanForHostGroups(ids []string) (plan *DeletePlan, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	groups, err := api.HostGroupsGet(Params{"groupids": ids})
+	if err != nil {
+		return
+	}
+	if len(groups) != len(ids) {
+		err = &ExpectedMore{len(ids), len(groups)}
+		return
+	}
+	deleted := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		if group.Internal == Internal {
+			err = &ValidationError{"host group", fmt.Sprintf("%q is internal and can't be deleted", group.Name)}
+			return
+		}
+		deleted[group.GroupId] = true
+	}
+
+	hosts, err := api.HostsGet(Params{"groupids": ids, selectHostGroups(v): []string{"groupid"}})
+	if err != nil {
+		return
+	}
+	var orphaned Hosts
+	for _, host := range hosts {
+		remains := false
+		for _, group := range host.GroupIds {
+			if !deleted[group.GroupId] {
+				remains = true
+				break
+			}
+		}
+		if !remains {
+			orphaned = append(orphaned, host)
+		}
+	}
+
+	plan, err = api.deletePlanForHosts(v, orphaned)
+	if err != nil {
+		return
+	}
+	plan.HostGroups = groups
+	return
+}
+
+// Returns plan for deleting hosts with given Ids and their items, applications and triggers.
+// Returns ExpectedMore if some hosts are not found.
+func (api *API) DeletePlanForHosts(ids []string) (plan *DeletePlan, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	hosts, err := api.HostsGet(Params{"hostids": ids})
+	if err != nil {
+		return
+	}
+	if len(hosts) != len(ids) {
+		err = &ExpectedMore{len(ids), len(hosts)}
+		return
+	}
+	return api.deletePlanForHosts(v, hosts)
+}
+
+func (api *API) deletePlanForHosts(v ServerVersion, hosts Hosts) (plan *DeletePlan, err error) {
+	plan = &DeletePlan{Hosts: hosts}
+	if len(hosts) == 0 {
+		return
+	}
+
+	ids := make([]string, len(hosts))
+	for i, host := range hosts {
+		ids[i] = host.HostId
+	}
+	plan.Items, err = api.ItemsGet(Params{"hostids": ids})
+	if err != nil {
+		return nil, err
+	}
+	if !v.AtLeast(5, 4) {
+		plan.Applications, err = api.ApplicationsGet(Params{"hostids": ids})
+		if err != nil {
+			return nil, err
+		}
+	}
+	plan.Triggers, err = api.TriggersGet(Params{"hostids": ids})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Executes plan: deletes hosts (Zabbix deletes their items, applications and triggers), then host groups.
+// Cleans HostId and GroupId in plan elements if calls succeed.
+func (api *API) DeletePlanExecute(plan *DeletePlan) (err error) {
+	if len(plan.Hosts) > 0 {
+		err = api.HostsDelete(plan.Hosts)
+		if err != nil {
+			return
+		}
+	}
+	if len(plan.HostGroups) > 0 {
+		err = api.HostGroupsDelete(plan.HostGroups)
+	}
+	return
+}
@@ -0,0 +1,77 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDeletePlan(t *testing.T) {
+	var calls []string
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return "5.0.0", nil
+		case "hostgroup.get":
+			return []map[string]string{{"groupid": "1", "name": "Old", "internal": "0"}, {"groupid": "2", "name": "Older", "internal": "0"}}, nil
+		case "host.get":
+			return []map[string]interface{}{
+				{"hostid": "10", "host": "only-old", "groups": []map[string]string{{"groupid": "1"}, {"groupid": "2"}}},
+				{"hostid": "11", "host": "shared", "groups": []map[string]string{{"groupid": "1"}, {"groupid": "3"}}},
+			}, nil
+		case "item.get":
+			var p map[string]interface{}
+			json.Unmarshal(params, &p)
+			if !reflect.DeepEqual(p["hostids"], []interface{}{"10"}) {
+				t.Errorf("Bad item.get params: %#v", p)
+			}
+			return []map[string]string{{"itemid": "21", "hostid": "10", "key_": "a"}, {"itemid": "22", "hostid": "10", "key_": "b"}}, nil
+		case "application.get":
+			return []map[string]string{{"applicationid": "31", "hostid": "10", "name": "App"}}, nil
+		case "trigger.get":
+			return []map[string]string{{"triggerid": "41", "description": "Trigger"}}, nil
+		case "host.delete":
+			calls = append(calls, method)
+			return map[string]interface{}{"hostids": []string{"10"}}, nil
+		case "hostgroup.delete":
+			calls = append(calls, method)
+			return map[string]interface{}{"groupids": []string{"1", "2"}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	plan, err := api.DeletePlanForHostGroups([]string{"1", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.HostGroups) != 2 || len(plan.Hosts) != 1 || plan.Hosts[0].HostId != "10" ||
+		len(plan.Items) != 2 || len(plan.Applications) != 1 || len(plan.Triggers) != 1 {
+		t.Errorf("Bad plan: %#v", plan)
+	}
+	expected := "Host groups (2): Old, Older\nHosts (1): only-old\nItems: 2\nApplications: 1\nTriggers: 1\n"
+	if plan.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, plan.String())
+	}
+	if len(calls) != 0 {
+		t.Errorf("Unexpected calls: %v", calls)
+	}
+
+	err = api.DeletePlanExecute(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(calls, []string{"host.delete", "hostgroup.delete"}) {
+		t.Errorf("Bad calls: %v", calls)
+	}
+	if plan.Hosts[0].HostId != "" || plan.HostGroups[0].GroupId != "" {
+		t.Errorf("Ids are not cleaned: %#v", plan)
+	}
+
+	_, err = api.DeletePlanForHostGroups([]string{"1", "2", "3"})
+	if _, ok := err.(*ExpectedMore); !ok {
+		t.Errorf("Expected ExpectedMore, got %v", err)
+	}
+}
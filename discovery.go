@@ -0,0 +1,352 @@
+package zabbix
+
+import (
+	"strconv"
+)
+
+type (
+	DiscoveryCheckType  int
+	DiscoveryRuleStatus int
+	DiscoveryStatus     int
+)
+
+const (
+	DiscoverySSH         DiscoveryCheckType = 0
+	DiscoveryLDAP        DiscoveryCheckType = 1
+	DiscoverySMTP        DiscoveryCheckType = 2
+	DiscoveryFTP         DiscoveryCheckType = 3
+	DiscoveryHTTP        DiscoveryCheckType = 4
+	DiscoveryPOP         DiscoveryCheckType = 5
+	DiscoveryNNTP        DiscoveryCheckType = 6
+	DiscoveryIMAP        DiscoveryCheckType = 7
+	DiscoveryTCP         DiscoveryCheckType = 8
+	DiscoveryZabbixAgent DiscoveryCheckType = 9
+	DiscoverySNMPv1      DiscoveryCheckType = 10
+	DiscoverySNMPv2      DiscoveryCheckType = 11
+	DiscoveryICMP        DiscoveryCheckType = 12
+	DiscoverySNMPv3      DiscoveryCheckType = 13
+	DiscoveryHTTPS       DiscoveryCheckType = 14
+	DiscoveryTelnet      DiscoveryCheckType = 15
+
+	DiscoveryRuleEnabled  DiscoveryRuleStatus = 0
+	DiscoveryRuleDisabled DiscoveryRuleStatus = 1
+
+	DiscoveryUp   DiscoveryStatus = 0
+	DiscoveryDown DiscoveryStatus = 1
+)
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/dcheck/object
+//
+// Key is used by Zabbix agent and SNMP checks. HostSource and NameSource are supported since Zabbix 4.4.
+type DiscoveryCheck struct {
+	DCheckId             string             `json:"dcheckid,omitempty"`
+	DRuleId              string             `json:"druleid,omitempty"`
+	Type                 DiscoveryCheckType `json:"type"`
+	Key                  string             `json:"key_,omitempty"`
+	Ports                string             `json:"ports,omitempty"`
+	Uniq                 int                `json:"uniq,omitempty"`
+	HostSource           int                `json:"host_source,omitempty"`
+	NameSource           int                `json:"name_source,omitempty"`
+	SNMPCommunity        string             `json:"snmp_community,omitempty"`
+	SNMPv3ContextName    string             `json:"snmpv3_contextname,omitempty"`
+	SNMPv3SecurityName   string             `json:"snmpv3_securityname,omitempty"`
+	SNMPv3SecurityLevel  int                `json:"snmpv3_securitylevel,omitempty"`
+	SNMPv3AuthProtocol   int                `json:"snmpv3_authprotocol,omitempty"`
+	SNMPv3AuthPassphrase string             `json:"snmpv3_authpassphrase,omitempty"`
+	SNMPv3PrivProtocol   int                `json:"snmpv3_privprotocol,omitempty"`
+	SNMPv3PrivPassphrase string             `json:"snmpv3_privpassphrase,omitempty"`
+}
+
+type DiscoveryChecks []DiscoveryCheck
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/drule/object
+//
+// Delay is in seconds. ProxyId is sent as proxy_hostid before Zabbix 7.0.
+type DiscoveryRule struct {
+	DRuleId string              `json:"druleid,omitempty"`
+	Name    string              `json:"name"`
+	IPRange string              `json:"iprange"`
+	Delay   int                 `json:"delay,omitempty"`
+	ProxyId string              `json:"proxyid,omitempty"`
+	Status  DiscoveryRuleStatus `json:"status"`
+
+	// Filled by DiscoveryRulesGet
+	Checks DiscoveryChecks `json:"dchecks,omitempty"`
+}
+
+type DiscoveryRules []DiscoveryRule
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/dservice/object
+//
+// LastUp and LastDown are Unix timestamps.
+type DiscoveredService struct {
+	DServiceId string          `json:"dserviceid"`
+	DHostId    string          `json:"dhostid"`
+	DCheckId   string          `json:"dcheckid"`
+	IP         string          `json:"ip"`
+	DNS        string          `json:"dns"`
+	Port       string          `json:"port"`
+	Status     DiscoveryStatus `json:"status"`
+	Value      string          `json:"value"`
+	LastUp     int64           `json:"lastup"`
+	LastDown   int64           `json:"lastdown"`
+}
+
+type DiscoveredServices []DiscoveredService
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/dhost/object
+//
+// LastUp and LastDown are Unix timestamps.
+type DiscoveredHost struct {
+	DHostId  string          `json:"dhostid"`
+	DRuleId  string          `json:"druleid"`
+	Status   DiscoveryStatus `json:"status"`
+	LastUp   int64           `json:"lastup"`
+	LastDown int64           `json:"lastdown"`
+
+	// Filled by DiscoveredHostsGet
+	Services DiscoveredServices `json:"dservices,omitempty"`
+}
+
+type DiscoveredHosts []DiscoveredHost
+
+// Wrapper for drule.get: https://www.zabbix.com/documentation/current/manual/api/reference/drule/get
+// Checks are selected by default.
+func (api *API) DiscoveryRulesGet(params Params) (res DiscoveryRules, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectDChecks"]; !present {
+		params["selectDChecks"] = "extend"
+	}
+	response, err := api.CallWithError("drule.get", params)
+	if err != nil {
+		return
+	}
+
+	maps := response.Result.([]interface{})
+	for _, m := range maps {
+		m := m.(map[string]interface{})
+		if proxyId, present := m["proxy_hostid"]; present {
+			m["proxyid"] = proxyId
+			delete(m, "proxy_hostid")
+		}
+		if m["proxyid"] == "0" {
+			delete(m, "proxyid")
+		}
+		normalizeTimeUnits(m, map[string]int{"delay": 1})
+	}
+	mapsToStructs(maps, &res)
+	return
+}
+
+// Gets discovery rule by Id only if there is exactly 1 matching discovery rule.
+func (api *API) DiscoveryRuleGetById(id string) (res *DiscoveryRule, err error) {
+	rules, err := api.DiscoveryRulesGet(Params{"druleids": id})
+	if err != nil {
+		return
+	}
+
+	if len(rules) == 1 {
+		res = &rules[0]
+	} else {
+		e := ExpectedOneResult(len(rules))
+		err = &e
+	}
+	return
+}
+
+// Gets discovery rule by name only if there is exactly 1 matching discovery rule.
+func (api *API) DiscoveryRuleGetByName(name string) (res *DiscoveryRule, err error) {
+	rules, err := api.DiscoveryRulesGet(Params{"filter": map[string]string{"name": name}})
+	if err != nil {
+		return
+	}
+
+	if len(rules) == 1 {
+		res = &rules[0]
+	} else {
+		e := ExpectedOneResult(len(rules))
+		err = &e
+	}
+	return
+}
+
+// Converts discovery rules to payload for drule.create and drule.update according to server version.
+// Version is detected only if rules use fields which depend on it.
+func (api *API) discoveryRulesPayload(rules DiscoveryRules) (res []map[string]interface{}, err error) {
+	var v ServerVersion
+	for _, rule := range rules {
+		if rule.ProxyId != "" {
+			v, err = api.ServerVersion()
+			if err != nil {
+				return
+			}
+			break
+		}
+	}
+
+	res, err = toMaps(rules)
+	if err != nil {
+		return
+	}
+
+	for i, rule := range rules {
+		m := res[i]
+		if rule.Delay != 0 {
+			m["delay"] = strconv.Itoa(rule.Delay)
+		}
+		if rule.ProxyId != "" && !v.AtLeast(7, 0) {
+			m["proxy_hostid"] = rule.ProxyId
+			delete(m, "proxyid")
+		}
+		checks, _ := m["dchecks"].([]interface{})
+		for _, c := range checks {
+			delete(c.(map[string]interface{}), "druleid")
+		}
+	}
+	return
+}
+
+// Wrapper for drule.create: https://www.zabbix.com/documentation/current/manual/api/reference/drule/create
+func (api *API) DiscoveryRulesCreate(rules DiscoveryRules) (err error) {
+	payload, err := api.discoveryRulesPayload(rules)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("drule.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	druleids := result["druleids"].([]interface{})
+	for i, id := range druleids {
+		rules[i].DRuleId = id.(string)
+	}
+	return
+}
+
+// Wrapper for drule.update: https://www.zabbix.com/documentation/current/manual/api/reference/drule/update
+// Checks are replaced by rule.Checks.
+func (api *API) DiscoveryRulesUpdate(rules DiscoveryRules) (err error) {
+	payload, err := api.discoveryRulesPayload(rules)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("drule.update", payload)
+	return
+}
+
+// Wrapper for drule.delete: https://www.zabbix.com/documentation/current/manual/api/reference/drule/delete
+// Cleans DRuleId in all rules elements if call succeed.
+func (api *API) DiscoveryRulesDelete(rules DiscoveryRules) (err error) {
+	ids := make([]string, len(rules))
+	for i, rule := range rules {
+		ids[i] = rule.DRuleId
+	}
+
+	err = api.DiscoveryRulesDeleteByIds(ids)
+	if err == nil {
+		for i := range rules {
+			rules[i].DRuleId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for drule.delete: https://www.zabbix.com/documentation/current/manual/api/reference/drule/delete
+func (api *API) DiscoveryRulesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("drule.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	druleids := result["druleids"].([]interface{})
+	if len(ids) != len(druleids) {
+		err = &ExpectedMore{len(ids), len(druleids)}
+	}
+	return
+}
+
+// Wrapper for dcheck.get: https://www.zabbix.com/documentation/current/manual/api/reference/dcheck/get
+func (api *API) DiscoveryChecksGet(params Params) (res DiscoveryChecks, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("dcheck.get", params)
+	if err != nil {
+		return
+	}
+
+	mapsToStructs(response.Result.([]interface{}), &res)
+	return
+}
+
+// Wrapper for dhost.get: https://www.zabbix.com/documentation/current/manual/api/reference/dhost/get
+// Services are selected by default.
+func (api *API) DiscoveredHostsGet(params Params) (res DiscoveredHosts, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectDServices"]; !present {
+		params["selectDServices"] = "extend"
+	}
+	response, err := api.CallWithError("dhost.get", params)
+	if err != nil {
+		return
+	}
+
+	mapsToStructs(response.Result.([]interface{}), &res)
+	return
+}
+
+// Gets discovered hosts by discovery rule Id.
+func (api *API) DiscoveredHostsGetByDiscoveryRuleId(id string) (res DiscoveredHosts, err error) {
+	return api.DiscoveredHostsGet(Params{"druleids": id})
+}
+
+// Wrapper for dservice.get: https://www.zabbix.com/documentation/current/manual/api/reference/dservice/get
+func (api *API) DiscoveredServicesGet(params Params) (res DiscoveredServices, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("dservice.get", params)
+	if err != nil {
+		return
+	}
+
+	mapsToStructs(response.Result.([]interface{}), &res)
+	return
+}
+
+// Returns discovered hosts which services' IP or DNS name don't match interfaces of any of given hosts.
+// Hosts should be obtained by HostsGet with "selectInterfaces" param, discovered hosts - with services.
+func UnmonitoredDiscoveredHosts(discovered DiscoveredHosts, hosts Hosts) (res DiscoveredHosts) {
+	known := make(map[string]bool)
+	for _, host := range hosts {
+		for _, iface := range host.Interfaces {
+			if iface.IP != "" {
+				known[iface.IP] = true
+			}
+			if iface.DNS != "" {
+				known[iface.DNS] = true
+			}
+		}
+	}
+
+	for _, dhost := range discovered {
+		found := false
+		for _, service := range dhost.Services {
+			if known[service.IP] || (service.DNS != "" && known[service.DNS]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, dhost)
+		}
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"testing"
+)
+
+func TestDiscoveryRules(t *testing.T) {
+	version := "6.0.0"
+	var payload []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "drule.create":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			return map[string]interface{}{"druleids": []string{"3"}}, nil
+		case "drule.get":
+			return []map[string]interface{}{{
+				"druleid": "3", "name": "LAN", "iprange": "192.168.1.1-254", "delay": "1h", "proxy_hostid": "11", "status": "0",
+				"dchecks": []map[string]string{{"dcheckid": "4", "druleid": "3", "type": "9", "key_": "system.uname", "ports": "10050", "uniq": "0"}},
+			}}, nil
+		case "dhost.get":
+			return []map[string]interface{}{
+				{"dhostid": "1", "druleid": "3", "status": "0", "lastup": "1600000000", "lastdown": "0",
+					"dservices": []map[string]string{{"dserviceid": "1", "dhostid": "1", "dcheckid": "4", "ip": "192.168.1.10", "dns": "", "port": "10050", "status": "0"}}},
+				{"dhostid": "2", "druleid": "3", "status": "0", "lastup": "1600000000", "lastdown": "0",
+					"dservices": []map[string]string{{"dserviceid": "2", "dhostid": "2", "dcheckid": "4", "ip": "192.168.1.20", "dns": "printer.lan", "port": "10050", "status": "0"}}},
+			}, nil
+		case "host.get":
+			return []map[string]interface{}{{"hostid": "1", "host": "h1",
+				"interfaces": []map[string]string{{"ip": "192.168.1.10", "dns": "", "port": "10050", "type": "1", "useip": "1", "main": "1"}}}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	rules := DiscoveryRules{{Name: "LAN", IPRange: "192.168.1.1-254", Delay: 3600, ProxyId: "11",
+		Checks: DiscoveryChecks{{Type: DiscoveryZabbixAgent, Key: "system.uname", Ports: "10050"}}}}
+	if err := api.DiscoveryRulesCreate(rules); err != nil {
+		t.Fatal(err)
+	}
+	if rules[0].DRuleId != "3" || payload[0]["proxy_hostid"] != "11" || payload[0]["proxyid"] != nil || payload[0]["delay"] != "3600" {
+		t.Errorf("Bad payload: %#v", payload[0])
+	}
+
+	rule, err := api.DiscoveryRuleGetById("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.ProxyId != "11" || rule.Delay != 3600 || len(rule.Checks) != 1 || rule.Checks[0].Type != DiscoveryZabbixAgent {
+		t.Errorf("Bad rule: %#v", rule)
+	}
+
+	dhosts, err := api.DiscoveredHostsGetByDiscoveryRuleId("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dhosts) != 2 || dhosts[0].LastUp != 1600000000 || len(dhosts[1].Services) != 1 {
+		t.Fatalf("Bad discovered hosts: %#v", dhosts)
+	}
+	hosts, err := api.HostsGet(Params{"selectInterfaces": "extend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmonitored := UnmonitoredDiscoveredHosts(dhosts, hosts)
+	if len(unmonitored) != 1 || unmonitored[0].DHostId != "2" {
+		t.Errorf("Bad unmonitored hosts: %#v", unmonitored)
+	}
+
+	version = "7.0.0"
+	api = NewAPI(srv.URL)
+	if err = api.DiscoveryRulesCreate(rules); err != nil {
+		t.Fatal(err)
+	}
+	if payload[0]["proxyid"] != "11" || payload[0]["proxy_hostid"] != nil {
+		t.Errorf("Bad payload: %#v", payload[0])
+	}
+}
@@ -0,0 +1,175 @@
+package zabbix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JSON-RPC error codes returned by Zabbix API.
+const (
+	ParseErrorCode     = -32700
+	InvalidRequestCode = -32600
+	MethodNotFoundCode = -32601
+	InvalidParamsCode  = -32602
+	InternalErrorCode  = -32603
+	ApplicationCode    = -32500
+)
+
+// Sentinel errors for classification with errors.Is. They are never returned directly;
+// API errors, ExpectedOneResult and others match them instead.
+var (
+	ErrNotFound         = errors.New("zabbix: object not found")
+	ErrAlreadyExists    = errors.New("zabbix: object already exists")
+	ErrPermissionDenied = errors.New("zabbix: permission denied")
+	ErrSessionExpired   = errors.New("zabbix: session expired")
+	ErrInvalidParams    = errors.New("zabbix: invalid params")
+	ErrUnsupported      = errors.New("zabbix: unsupported on this server version")
+)
+
+// Is classifies API error by code and data, so errors.Is(err, ErrAlreadyExists) and
+// the like work without string matching in callers.
+func (e *Error) Is(target error) bool {
+	data := strings.ToLower(e.Data)
+	switch target {
+	case ErrNotFound:
+		return strings.Contains(data, "does not exist") || strings.Contains(data, "not found")
+	case ErrAlreadyExists:
+		return strings.Contains(data, "already exist")
+	case ErrPermissionDenied:
+		return strings.Contains(data, "no permissions") || strings.Contains(data, "permission denied") ||
+			strings.Contains(data, "do not have permission")
+	case ErrSessionExpired:
+		return strings.Contains(data, "session terminated") || strings.Contains(data, "not authorised") ||
+			strings.Contains(data, "not authorized")
+	case ErrInvalidParams:
+		// Zabbix uses the same code for more specific errors
+		if e.Is(ErrAlreadyExists) || e.Is(ErrSessionExpired) || e.Is(ErrPermissionDenied) {
+			return false
+		}
+		return e.Code == InvalidParamsCode || e.Code == InvalidRequestCode || strings.Contains(data, "invalid params") ||
+			strings.Contains(data, "invalid parameter")
+	}
+	return false
+}
+
+// Zero results match ErrNotFound.
+func (e *ExpectedOneResult) Is(target error) bool {
+	return target == ErrNotFound && *e == 0
+}
+
+// UnsupportedError is returned by typed wrappers when requested feature is not available on server version.
+// It matches ErrUnsupported.
+type UnsupportedError struct {
+	Feature string
+	Version ServerVersion
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s is not supported by Zabbix %s", e.Feature, e.Version)
+}
+
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
+
+// ValidationError is returned by typed wrappers when object fails client-side validation before API call.
+// It matches ErrInvalidParams.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidParams
+}
+
+// CallError wraps any error returned by Call and CallWithError with API method name and request id.
+type CallError struct {
+	Method string
+	Id     int32
+	Err    error
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("%s (id %d): %s", e.Method, e.Id, e.Err)
+}
+
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
+// RollbackError is returned when operation fails and undoing its partial changes fails too,
+// for example, when HostClone can't delete new host. It wraps original error.
+type RollbackError struct {
+	Err         error // original error
+	RollbackErr error
+}
+
+func (e *RollbackError) Error() string {
+	return fmt.Sprintf("%s (rollback failed: %s)", e.Err, e.RollbackErr)
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPError is returned when server responds with non-200 status or body which is not JSON-RPC response,
+// for example, HTML error page from web server or PHP.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+	Err        error // unmarshal error, if any
+}
+
+func (e *HTTPError) Error() string {
+	body := string(e.Body)
+	if len(body) > 200 {
+		body = body[:200] + "..."
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("HTTP %s: %s: %q", e.Status, e.Err, body)
+	}
+	return fmt.Sprintf("HTTP %s: %q", e.Status, body)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Returns true if err is (or wraps) API error for missing object, or ExpectedOneResult with zero results.
+// Note that Zabbix often reports missing objects as permission problem, so IsPermissionDenied may be true too.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// Returns true if err is (or wraps) API error for already existing object.
+func IsAlreadyExists(err error) bool {
+	return errors.Is(err, ErrAlreadyExists)
+}
+
+// Returns true if err is (or wraps) API error for insufficient permissions.
+func IsPermissionDenied(err error) bool {
+	return errors.Is(err, ErrPermissionDenied)
+}
+
+// Returns true if err is (or wraps) API error for expired or invalid session; caller should Login() again.
+func IsSessionExpired(err error) bool {
+	return errors.Is(err, ErrSessionExpired)
+}
+
+// Returns true if err is (or wraps) *UnsupportedError.
+func IsUnsupported(err error) bool {
+	return errors.Is(err, ErrUnsupported)
+}
+
+// Returns true if err is (or wraps) API error for invalid request or params, or *ValidationError.
+// API errors matching IsAlreadyExists, IsSessionExpired or IsPermissionDenied don't match it.
+func IsInvalidParams(err error) bool {
+	return errors.Is(err, ErrInvalidParams)
+}
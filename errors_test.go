@@ -0,0 +1,77 @@
+package zabbix_test
+
+import (
+	. "."
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorClassification(t *testing.T) {
+	wrap := func(e *Error) error {
+		return &CallError{Method: "host.create", Id: 1, Err: e}
+	}
+
+	err := wrap(&Error{-32602, "Invalid params.", "Host with the same name \"foo\" already exists."})
+	if !IsAlreadyExists(err) || IsInvalidParams(err) || IsNotFound(err) {
+		t.Errorf("Bad classification: %s", err)
+	}
+
+	err = wrap(&Error{-32500, "Application error.", "No permissions to referred object or it does not exist!"})
+	if !IsNotFound(err) || !IsPermissionDenied(err) || IsAlreadyExists(err) {
+		t.Errorf("Bad classification: %s", err)
+	}
+
+	err = wrap(&Error{-32602, "Invalid params.", "No permissions to referred object or it does not exist!"})
+	if !IsPermissionDenied(err) || IsInvalidParams(err) {
+		t.Errorf("Bad classification: %s", err)
+	}
+
+	err = wrap(&Error{-32602, "Invalid params.", "Incorrect value for field \"name\": cannot be empty."})
+	if !IsInvalidParams(err) || IsAlreadyExists(err) || IsPermissionDenied(err) {
+		t.Errorf("Bad classification: %s", err)
+	}
+
+	err = wrap(&Error{-32602, "Invalid params.", "Session terminated, re-login, please."})
+	if !IsSessionExpired(err) || IsInvalidParams(err) {
+		t.Errorf("Bad classification: %s", err)
+	}
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != -32602 {
+		t.Errorf("errors.As failed: %s", err)
+	}
+
+	e := ExpectedOneResult(0)
+	if !IsNotFound(fmt.Errorf("lookup: %w", &e)) {
+		t.Errorf("Bad classification: %s", &e)
+	}
+	e = ExpectedOneResult(2)
+	if IsNotFound(&e) {
+		t.Errorf("Bad classification: %s", &e)
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	status := http.StatusBadGateway
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte("<html>oops</html>"))
+	}))
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	for _, status = range []int{http.StatusBadGateway, http.StatusOK} {
+		_, err := api.Call("apiinfo.version", Params{})
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.StatusCode != status {
+			t.Errorf("Expected *HTTPError with status %d, got %#v", status, err)
+		}
+		var callErr *CallError
+		if !errors.As(err, &callErr) || callErr.Method != "apiinfo.version" || callErr.Id == 0 {
+			t.Errorf("Expected *CallError, got %#v", err)
+		}
+	}
+}
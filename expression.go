@@ -0,0 +1,654 @@
+package zabbix
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Node of trigger or calculated item expression syntax tree.
+type ExprNode interface {
+	exprNode()
+}
+
+// Number (possibly with suffix like "5m" or "10K", or exponent like "1e3") or history function period like "#3" or "1h:now/h".
+// Empty constant is used for omitted function arguments.
+type ExprConstant struct {
+	Value string
+}
+
+// Quoted string.
+type ExprString struct {
+	Value string
+}
+
+// User macro like "{$LIMIT}", LLD macro like "{#FSNAME}" or built-in macro like "{TRIGGER.VALUE}".
+type ExprMacro struct {
+	Macro string
+}
+
+// Item query "/host/key?[filter]" referencing item. Host may be empty (item of the same host),
+// "{HOST.HOST}" or "*" in aggregate functions. Filter is kept as is, without "?[" and "]".
+type ExprQuery struct {
+	Host   string
+	Key    string
+	Filter string
+}
+
+// Function call. For legacy "{host:key.func(params)}" references Legacy is true,
+// the first argument is *ExprQuery and others are *ExprString or *ExprConstant with raw parameters.
+type ExprFunction struct {
+	Name   string
+	Args   []ExprNode
+	Legacy bool
+}
+
+// Unary operator: "-" or "not".
+type ExprUnary struct {
+	Op string
+	X  ExprNode
+}
+
+// Binary operator: "*", "/", "+", "-", "<", "<=", ">", ">=", "=", "<>", "and" or "or".
+// Legacy "#", "&" and "|" are parsed as "<>", "and" and "or".
+type ExprBinary struct {
+	Op   string
+	X, Y ExprNode
+}
+
+func (*ExprConstant) exprNode() {}
+func (*ExprString) exprNode()   {}
+func (*ExprMacro) exprNode()    {}
+func (*ExprQuery) exprNode()    {}
+func (*ExprFunction) exprNode() {}
+func (*ExprUnary) exprNode()    {}
+func (*ExprBinary) exprNode()   {}
+
+// Parsed trigger or calculated item expression in legacy (before Zabbix 5.4, "{host:key.func()}")
+// or new ("func(/host/key)") syntax. Legacy calculated item syntax ("func("host:key")") is not supported.
+type Expression struct {
+	Root   ExprNode
+	Legacy bool // expression contains legacy item references
+}
+
+// ExpressionError is returned by ParseExpression for malformed expressions and by ConvertToNew for
+// legacy functions which can't be converted. It matches ErrInvalidParams.
+type ExpressionError struct {
+	Expression string
+	Pos        int
+	Reason     string
+}
+
+func (e *ExpressionError) Error() string {
+	if e.Pos < 0 {
+		return fmt.Sprintf("invalid expression %q: %s", e.Expression, e.Reason)
+	}
+	return fmt.Sprintf("invalid expression %q at position %d: %s", e.Expression, e.Pos, e.Reason)
+}
+
+func (e *ExpressionError) Is(target error) bool {
+	return target == ErrInvalidParams
+}
+
+// Operator precedence, from lowest to highest.
+var exprPrecedence = map[string]int{
+	"or": 1, "and": 2,
+	"=": 3, "<>": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6,
+}
+
+const exprUnaryPrecedence = 7
+
+// Legacy operators and their replacements.
+var legacyOperators = map[string]string{"#": "<>", "&": "and", "|": "or"}
+
+// Parses trigger or calculated item expression.
+// Function id placeholders like "{13083}" in unexpanded expressions are rejected.
+func ParseExpression(s string) (expr *Expression, err error) {
+	p := &exprParser{s: s}
+	root, err := p.expression(0)
+	if err != nil {
+		return
+	}
+	p.skipSpaces()
+	if p.pos != len(s) {
+		return nil, p.error(fmt.Sprintf("unexpected character %q", s[p.pos]))
+	}
+	expr = &Expression{Root: root, Legacy: p.legacy}
+	return
+}
+
+type exprParser struct {
+	s      string
+	pos    int
+	legacy bool
+}
+
+func (p *exprParser) error(reason string) error {
+	return &ExpressionError{Expression: p.s, Pos: p.pos, Reason: reason}
+}
+
+func (p *exprParser) skipSpaces() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// Returns binary operator at current position without consuming it.
+func (p *exprParser) peekOperator() (op string, size int) {
+	rest := p.s[p.pos:]
+	for _, op := range []string{"<=", ">=", "<>", "*", "/", "+", "-", "<", ">", "=", "#", "&", "|"} {
+		if strings.HasPrefix(rest, op) {
+			if legacy := legacyOperators[op]; legacy != "" {
+				return legacy, 1
+			}
+			return op, len(op)
+		}
+	}
+	for _, op := range []string{"and", "or"} {
+		if p.keyword(op) {
+			return op, len(op)
+		}
+	}
+	return
+}
+
+// Reports whether keyword is at current position and is not a part of longer word.
+func (p *exprParser) keyword(word string) bool {
+	rest := p.s[p.pos:]
+	if !strings.HasPrefix(rest, word) {
+		return false
+	}
+	if len(rest) == len(word) {
+		return true
+	}
+	c := rest[len(word)]
+	return !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_')
+}
+
+// Parses binary operators with precedence not lower than minPrecedence (precedence climbing).
+func (p *exprParser) expression(minPrecedence int) (node ExprNode, err error) {
+	node, err = p.unary()
+	if err != nil {
+		return
+	}
+	for {
+		p.skipSpaces()
+		op, size := p.peekOperator()
+		precedence := exprPrecedence[op]
+		if op == "" || precedence <= minPrecedence {
+			return
+		}
+		p.pos += size
+
+		var right ExprNode
+		right, err = p.expression(precedence)
+		if err != nil {
+			return
+		}
+		node = &ExprBinary{Op: op, X: node, Y: right}
+	}
+}
+
+func (p *exprParser) unary() (node ExprNode, err error) {
+	p.skipSpaces()
+	switch {
+	case p.pos < len(p.s) && p.s[p.pos] == '-':
+		p.pos++
+		var x ExprNode
+		x, err = p.unary()
+		node = &ExprUnary{Op: "-", X: x}
+		return
+	case p.keyword("not"):
+		p.pos += 3
+		var x ExprNode
+		x, err = p.unary()
+		node = &ExprUnary{Op: "not", X: x}
+		return
+	}
+	return p.operand()
+}
+
+// Number with optional suffix or exponent: "5", ".5", "10K", "1.5e-3".
+var exprNumberRe = regexp.MustCompile(`^(?:[0-9]+(?:\.[0-9]+)?|\.[0-9]+)(?:[eE][+-]?[0-9]+|[KMGTsmhdw])?`)
+
+func (p *exprParser) operand() (node ExprNode, err error) {
+	if p.pos == len(p.s) {
+		return nil, p.error("unexpected end of expression")
+	}
+	rest := p.s[p.pos:]
+	c := rest[0]
+	switch {
+	case c == '(':
+		p.pos++
+		node, err = p.expression(0)
+		if err != nil {
+			return
+		}
+		p.skipSpaces()
+		if p.pos == len(p.s) || p.s[p.pos] != ')' {
+			return nil, p.error("expected ')'")
+		}
+		p.pos++
+		return
+
+	case c == '"':
+		var s string
+		s, err = p.quoted()
+		node = &ExprString{s}
+		return
+
+	case c == '{':
+		return p.brace()
+
+	case c >= '0' && c <= '9' || c == '.' && len(rest) > 1 && rest[1] >= '0' && rest[1] <= '9':
+		m := exprNumberRe.FindString(rest)
+		p.pos += len(m)
+		node = &ExprConstant{m}
+		return
+
+	case c >= 'a' && c <= 'z':
+		start := p.pos
+		for p.pos < len(p.s) && (p.s[p.pos] >= 'a' && p.s[p.pos] <= 'z' || p.s[p.pos] == '_') {
+			p.pos++
+		}
+		name := p.s[start:p.pos]
+		if p.pos == len(p.s) || p.s[p.pos] != '(' {
+			p.pos = start
+			return nil, p.error(fmt.Sprintf("unexpected word %q", name))
+		}
+		var args []ExprNode
+		args, err = p.arguments()
+		node = &ExprFunction{Name: name, Args: args}
+		return
+	}
+	return nil, p.error(fmt.Sprintf("unexpected character %q", c))
+}
+
+// Parses quoted string with \" and \\ escapes at current position.
+func (p *exprParser) quoted() (s string, err error) {
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.s) && (p.s[p.pos+1] == '"' || p.s[p.pos+1] == '\\'):
+			b.WriteByte(p.s[p.pos+1])
+			p.pos += 2
+		case c == '"':
+			p.pos++
+			s = b.String()
+			return
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	p.pos = start
+	err = p.error("unterminated string")
+	return
+}
+
+// Parses macro or legacy item reference "{host:key.func(params)}" at current position.
+func (p *exprParser) brace() (node ExprNode, err error) {
+	kp := itemKeyParser{s: p.s, pos: p.pos}
+	if kp.skipMacro() {
+		node = &ExprMacro{p.s[p.pos:kp.pos]}
+		p.pos = kp.pos
+		return
+	}
+
+	end := strings.IndexByte(p.s[p.pos:], '}')
+	colon := strings.IndexByte(p.s[p.pos:], ':')
+	if end < 0 {
+		return nil, p.error("unterminated macro or item reference")
+	}
+	if isFunctionId(p.s[p.pos+1 : p.pos+end]) {
+		return nil, p.error("function id placeholder, expression should be read with expandExpression")
+	}
+	if colon < 0 || colon > end {
+		// built-in macro like {TRIGGER.VALUE}
+		node = &ExprMacro{p.s[p.pos : p.pos+end+1]}
+		p.pos += end + 1
+		return
+	}
+	return p.legacyReference(colon)
+}
+
+// Reports whether s is function id of "{13083}" placeholder used by server in unexpanded expressions.
+func isFunctionId(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *exprParser) legacyReference(colon int) (node ExprNode, err error) {
+	start := p.pos
+	host := p.s[p.pos+1 : p.pos+colon]
+	p.pos += colon + 1
+
+	// key name includes function name if key has no parameters
+	kp := itemKeyParser{s: p.s, pos: p.pos}
+	for kp.pos < len(p.s) && isItemKeyNameChar(p.s[kp.pos]) {
+		kp.pos++
+	}
+	if kp.pos == p.pos {
+		return nil, p.error("expected item key")
+	}
+	var key, function string
+	if kp.pos < len(p.s) && p.s[kp.pos] == '[' {
+		if _, err = kp.params(); err != nil {
+			return nil, p.error("bad item key: " + err.(*ItemKeyError).Reason)
+		}
+		key = p.s[p.pos:kp.pos]
+		if kp.pos == len(p.s) || p.s[kp.pos] != '.' {
+			p.pos = kp.pos
+			return nil, p.error("expected '.' and function after item key")
+		}
+		kp.pos++
+		fstart := kp.pos
+		for kp.pos < len(p.s) && p.s[kp.pos] >= 'a' && p.s[kp.pos] <= 'z' {
+			kp.pos++
+		}
+		function = p.s[fstart:kp.pos]
+	} else {
+		name := p.s[p.pos:kp.pos]
+		dot := strings.LastIndexByte(name, '.')
+		if dot <= 0 {
+			return nil, p.error("expected item key and function")
+		}
+		key, function = name[:dot], name[dot+1:]
+	}
+	p.pos = kp.pos
+	if host == "" || function == "" || p.pos == len(p.s) || p.s[p.pos] != '(' {
+		p.pos = start
+		return nil, p.error("bad item reference")
+	}
+
+	// legacy parameters are raw strings, possibly quoted
+	p.pos++
+	args := []ExprNode{&ExprQuery{Host: host, Key: key}}
+	for {
+		p.skipSpaces()
+		if p.pos < len(p.s) && p.s[p.pos] == '"' {
+			var s string
+			if s, err = p.quoted(); err != nil {
+				return
+			}
+			args = append(args, &ExprString{s})
+			p.skipSpaces()
+		} else {
+			pstart := p.pos
+			for p.pos < len(p.s) && p.s[p.pos] != ',' && p.s[p.pos] != ')' {
+				p.pos++
+			}
+			args = append(args, &ExprConstant{strings.TrimSpace(p.s[pstart:p.pos])})
+		}
+		if p.pos == len(p.s) {
+			return nil, p.error("unterminated function parameters")
+		}
+		if p.s[p.pos] == ')' {
+			p.pos++
+			break
+		}
+		if p.s[p.pos] != ',' {
+			return nil, p.error("expected ',' or ')'")
+		}
+		p.pos++
+	}
+	if c, ok := args[1].(*ExprConstant); ok && len(args) == 2 && c.Value == "" {
+		args = args[:1] // func() has no parameters
+	}
+	if p.pos == len(p.s) || p.s[p.pos] != '}' {
+		return nil, p.error("expected '}'")
+	}
+	p.pos++
+	p.legacy = true
+	node = &ExprFunction{Name: function, Args: args, Legacy: true}
+	return
+}
+
+var exprPeriodRe = regexp.MustCompile(`^(#?[0-9]+[smhdwKMGT]?)?(:now[0-9a-zA-Z/+-]*)?$`)
+
+// Parses function arguments in parentheses at current position.
+func (p *exprParser) arguments() (args []ExprNode, err error) {
+	p.pos++ // skip '('
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == ')' {
+		p.pos++
+		return
+	}
+	for {
+		var arg ExprNode
+		arg, err = p.argument()
+		if err != nil {
+			return
+		}
+		args = append(args, arg)
+
+		p.skipSpaces()
+		if p.pos == len(p.s) {
+			return nil, p.error("unterminated function arguments")
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return
+		default:
+			return nil, p.error(fmt.Sprintf("unexpected character %q, expected ',' or ')'", p.s[p.pos]))
+		}
+	}
+}
+
+func (p *exprParser) argument() (node ExprNode, err error) {
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == '/' {
+		return p.query()
+	}
+
+	// empty arguments and periods
+	end := p.pos
+	for end < len(p.s) && strings.IndexByte(",) \t\r\n", p.s[end]) < 0 {
+		end++
+	}
+	next := end
+	for next < len(p.s) && strings.IndexByte(" \t\r\n", p.s[next]) >= 0 {
+		next++
+	}
+	if raw := p.s[p.pos:end]; exprPeriodRe.MatchString(raw) && next < len(p.s) && strings.IndexByte(",)", p.s[next]) >= 0 {
+		p.pos = end
+		node = &ExprConstant{raw}
+		return
+	}
+	return p.expression(0)
+}
+
+// Parses item query "/host/key?[filter]" at current position.
+func (p *exprParser) query() (node ExprNode, err error) {
+	p.pos++ // skip '/'
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] != '/' {
+		if p.s[p.pos] == '{' {
+			kp := itemKeyParser{s: p.s, pos: p.pos}
+			if kp.skipMacro() {
+				p.pos = kp.pos
+				continue
+			}
+			if end := strings.IndexByte(p.s[p.pos:], '}'); end > 0 {
+				p.pos += end + 1
+				continue
+			}
+		}
+		if strings.IndexByte(",()", p.s[p.pos]) >= 0 {
+			return nil, p.error("expected '/' after host")
+		}
+		p.pos++
+	}
+	if p.pos == len(p.s) {
+		return nil, p.error("expected '/' after host")
+	}
+	query := &ExprQuery{Host: p.s[start:p.pos]}
+	p.pos++
+
+	kp := itemKeyParser{s: p.s, pos: p.pos}
+	for kp.pos < len(p.s) && (isItemKeyNameChar(p.s[kp.pos]) || p.s[kp.pos] == '*') {
+		kp.pos++
+	}
+	if kp.pos == p.pos {
+		return nil, p.error("expected item key")
+	}
+	if kp.pos < len(p.s) && p.s[kp.pos] == '[' {
+		if _, err = kp.params(); err != nil {
+			return nil, p.error("bad item key: " + err.(*ItemKeyError).Reason)
+		}
+	}
+	query.Key = p.s[p.pos:kp.pos]
+	p.pos = kp.pos
+
+	if strings.HasPrefix(p.s[p.pos:], "?[") {
+		p.pos += 2
+		fstart := p.pos
+		depth := 1
+		for p.pos < len(p.s) && depth > 0 {
+			switch p.s[p.pos] {
+			case '"':
+				if _, err = p.quoted(); err != nil {
+					return
+				}
+				continue
+			case '[':
+				depth++
+			case ']':
+				depth--
+			}
+			p.pos++
+		}
+		if depth > 0 {
+			return nil, p.error("unterminated filter")
+		}
+		query.Filter = p.s[fstart : p.pos-1]
+	}
+	node = query
+	return
+}
+
+// Formats expression in its syntax: legacy references are formatted as "{host:key.func(params)}".
+func (expr *Expression) String() string {
+	return formatExpr(expr.Root, 0, false)
+}
+
+func formatExpr(node ExprNode, parentPrecedence int, right bool) string {
+	switch n := node.(type) {
+	case *ExprConstant:
+		return n.Value
+	case *ExprString:
+		return quoteExprString(n.Value)
+	case *ExprMacro:
+		return n.Macro
+	case *ExprQuery:
+		s := "/" + n.Host + "/" + n.Key
+		if n.Filter != "" {
+			s += "?[" + n.Filter + "]"
+		}
+		return s
+	case *ExprFunction:
+		args := make([]string, len(n.Args))
+		for i, arg := range n.Args {
+			args[i] = formatExpr(arg, 0, false)
+		}
+		if n.Legacy {
+			query := n.Args[0].(*ExprQuery)
+			return "{" + query.Host + ":" + query.Key + "." + n.Name + "(" + strings.Join(args[1:], ",") + ")}"
+		}
+		return n.Name + "(" + strings.Join(args, ",") + ")"
+	case *ExprUnary:
+		s := n.Op
+		x := formatExpr(n.X, exprUnaryPrecedence, false)
+		if n.Op == "not" || strings.HasPrefix(x, "-") {
+			s += " " // "- -1", not "--1"
+		}
+		s += x
+		if parentPrecedence > exprUnaryPrecedence {
+			s = "(" + s + ")"
+		}
+		return s
+	case *ExprBinary:
+		precedence := exprPrecedence[n.Op]
+		op := n.Op
+		if op == "and" || op == "or" {
+			op = " " + op + " "
+		}
+		y := formatExpr(n.Y, precedence, true)
+		if op == "-" && strings.HasPrefix(y, "-") {
+			y = " " + y
+		}
+		s := formatExpr(n.X, precedence, false) + op + y
+		if precedence < parentPrecedence || (right && precedence == parentPrecedence) {
+			s = "(" + s + ")"
+		}
+		return s
+	}
+	return ""
+}
+
+func quoteExprString(s string) string {
+	return `"` + strings.Replace(strings.Replace(s, `\`, `\\`, -1), `"`, `\"`, -1) + `"`
+}
+
+// Calls f for all nodes of expression in depth-first order.
+func (expr *Expression) Walk(f func(node ExprNode)) {
+	walkExpr(expr.Root, f)
+}
+
+func walkExpr(node ExprNode, f func(node ExprNode)) {
+	if node == nil {
+		return
+	}
+	f(node)
+	switch n := node.(type) {
+	case *ExprFunction:
+		for _, arg := range n.Args {
+			walkExpr(arg, f)
+		}
+	case *ExprUnary:
+		walkExpr(n.X, f)
+	case *ExprBinary:
+		walkExpr(n.X, f)
+		walkExpr(n.Y, f)
+	}
+}
+
+// Returns unique host and key pairs referenced by expression in order of appearance; filters are ignored.
+func (expr *Expression) References() (res []ExprQuery) {
+	seen := make(map[ExprQuery]bool)
+	expr.Walk(func(node ExprNode) {
+		if q, ok := node.(*ExprQuery); ok {
+			ref := ExprQuery{Host: q.Host, Key: q.Key}
+			if !seen[ref] {
+				seen[ref] = true
+				res = append(res, ref)
+			}
+		}
+	})
+	return
+}
+
+// Replaces host name in all item references, for example when cloning host or template.
+func (expr *Expression) RenameHost(oldHost, newHost string) {
+	expr.Walk(func(node ExprNode) {
+		if q, ok := node.(*ExprQuery); ok && q.Host == oldHost {
+			q.Host = newHost
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package zabbix
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Returns copy of expression with legacy item references converted to new syntax used since Zabbix 5.4,
+// following https://www.zabbix.com/documentation/5.4/manual/installation/upgrade_notes_540.
+// Time shifts are converted to periods like "1h:now-1d". count() without operator gets "eq" for numeric
+// patterns and "like" for others. Returns *ExpressionError for functions which can't be converted.
+func (expr *Expression) ConvertToNew() (res *Expression, err error) {
+	root, err := convertExpr(expr.Root)
+	if err != nil {
+		err = &ExpressionError{Expression: expr.String(), Pos: -1, Reason: err.Error()}
+		return
+	}
+	res = &Expression{Root: root}
+	return
+}
+
+func convertExpr(node ExprNode) (res ExprNode, err error) {
+	switch n := node.(type) {
+	case *ExprFunction:
+		if n.Legacy {
+			return convertLegacyFunction(n)
+		}
+		f := &ExprFunction{Name: n.Name, Args: make([]ExprNode, len(n.Args))}
+		for i, arg := range n.Args {
+			if f.Args[i], err = convertExpr(arg); err != nil {
+				return
+			}
+		}
+		return f, nil
+	case *ExprUnary:
+		x, err := convertExpr(n.X)
+		return &ExprUnary{Op: n.Op, X: x}, err
+	case *ExprBinary:
+		x, err := convertExpr(n.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := convertExpr(n.Y)
+		return &ExprBinary{Op: n.Op, X: x, Y: y}, err
+	case *ExprQuery:
+		q := *n
+		return &q, nil
+	case *ExprConstant:
+		c := *n
+		return &c, nil
+	case *ExprString:
+		s := *n
+		return &s, nil
+	case *ExprMacro:
+		m := *n
+		return &m, nil
+	}
+	return nil, fmt.Errorf("unexpected node %T", node)
+}
+
+var legacyNumericRe = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?[KMGTsmhdw]?$`)
+
+// Returns raw value of legacy parameter with given index, or empty string if it is omitted.
+func legacyParam(f *ExprFunction, i int) string {
+	if i+1 >= len(f.Args) {
+		return ""
+	}
+	switch a := f.Args[i+1].(type) {
+	case *ExprConstant:
+		return a.Value
+	case *ExprString:
+		return a.Value
+	}
+	return ""
+}
+
+// Combines legacy period and time shift parameters into new period argument.
+func legacyPeriod(period, shift string) ExprNode {
+	if shift == "" || shift == "0" {
+		return &ExprConstant{period}
+	}
+	if period == "" || period == "0" {
+		period = "#1"
+	}
+	return &ExprConstant{period + ":now-" + shift}
+}
+
+// Removes trailing empty arguments.
+func trimArgs(args []ExprNode) []ExprNode {
+	for len(args) > 1 {
+		if c, ok := args[len(args)-1].(*ExprConstant); ok && c.Value == "" {
+			args = args[:len(args)-1]
+			continue
+		}
+		break
+	}
+	return args
+}
+
+func convertLegacyFunction(f *ExprFunction) (res ExprNode, err error) {
+	q := *f.Args[0].(*ExprQuery)
+	query := &q
+	param := func(i int) string { return legacyParam(f, i) }
+	str := func(i int) ExprNode {
+		if s := param(i); s != "" {
+			return &ExprString{s}
+		}
+		return &ExprConstant{""}
+	}
+	call := func(name string, args ...ExprNode) *ExprFunction {
+		return &ExprFunction{Name: name, Args: trimArgs(append([]ExprNode{query}, args...))}
+	}
+
+	switch f.Name {
+	case "last":
+		return call("last", legacyPeriod(lastPeriod(param(0)), param(1))), nil
+	case "prev":
+		return call("last", &ExprConstant{"#2"}), nil
+	case "min", "max", "avg", "sum":
+		return call(f.Name, legacyPeriod(param(0), param(1))), nil
+	case "count":
+		pattern, op := param(1), param(2)
+		if pattern != "" && op == "" {
+			op = "like"
+			if legacyNumericRe.MatchString(pattern) {
+				op = "eq"
+			}
+		}
+		args := []ExprNode{legacyPeriod(param(0), param(3))}
+		if op != "" {
+			args = append(args, &ExprString{op}, &ExprString{pattern})
+		}
+		return call("count", args...), nil
+	case "nodata":
+		args := []ExprNode{&ExprConstant{param(0)}}
+		if param(1) != "" {
+			args = append(args, &ExprString{param(1)})
+		}
+		return call("nodata", args...), nil
+	case "change":
+		return call("change"), nil
+	case "abschange":
+		return &ExprFunction{Name: "abs", Args: []ExprNode{call("change")}}, nil
+	case "delta":
+		period := legacyPeriod(param(0), param(1))
+		return &ExprBinary{Op: "-", X: call("max", period), Y: call("min", period)}, nil
+	case "diff":
+		return &ExprBinary{Op: "<>", X: call("last", &ExprConstant{"#1"}), Y: call("last", &ExprConstant{"#2"})}, nil
+	case "str", "regexp", "iregexp":
+		op := map[string]string{"str": "like", "regexp": "regexp", "iregexp": "iregexp"}[f.Name]
+		return call("find", &ExprConstant{param(1)}, &ExprString{op}, str(0)), nil
+	case "strlen":
+		return &ExprFunction{Name: "length", Args: []ExprNode{call("last", legacyPeriod(lastPeriod(param(0)), param(1)))}}, nil
+	case "fuzzytime":
+		return call("fuzzytime", &ExprConstant{param(0)}), nil
+	case "forecast":
+		return call("forecast", legacyPeriod(param(0), param(1)), &ExprConstant{param(2)}, str(3), str(4)), nil
+	case "timeleft":
+		return call("timeleft", legacyPeriod(param(0), param(1)), &ExprConstant{param(2)}, str(3)), nil
+	case "percentile":
+		return call("percentile", legacyPeriod(param(0), param(1)), &ExprConstant{param(2)}), nil
+	case "band":
+		last := call("last", legacyPeriod(lastPeriod(param(0)), param(2)))
+		return &ExprFunction{Name: "bitand", Args: []ExprNode{last, &ExprConstant{param(1)}}}, nil
+	case "logeventid", "logsource":
+		return call(f.Name, &ExprConstant{""}, str(0)), nil
+	case "logseverity":
+		return call("logseverity"), nil
+	case "date", "time", "dayofweek", "dayofmonth", "now":
+		return &ExprFunction{Name: f.Name}, nil
+	}
+	return nil, fmt.Errorf("can't convert function %s()", f.Name)
+}
+
+// Returns legacy period of last() and similar functions if it is a count of values; seconds are ignored there.
+func lastPeriod(period string) string {
+	if len(period) > 1 && period[0] == '#' && period != "#1" {
+		return period
+	}
+	return ""
+}
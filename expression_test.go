@@ -0,0 +1,203 @@
+package zabbix_test
+
+import (
+	. "."
+	"reflect"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	for s, expected := range map[string]*Expression{
+		"last(/host/agent.ping)=1": {Root: &ExprBinary{Op: "=",
+			X: &ExprFunction{Name: "last", Args: []ExprNode{&ExprQuery{Host: "host", Key: "agent.ping"}}},
+			Y: &ExprConstant{"1"},
+		}},
+		`avg(/host/vfs.fs.size["/",pfree],5m:now-1d) < {$LIMIT}`: {Root: &ExprBinary{Op: "<",
+			X: &ExprFunction{Name: "avg", Args: []ExprNode{
+				&ExprQuery{Host: "host", Key: `vfs.fs.size["/",pfree]`}, &ExprConstant{"5m:now-1d"},
+			}},
+			Y: &ExprMacro{"{$LIMIT}"},
+		}},
+		`count(/*/net.if.in[*]?[group="Linux servers" and tag="a]"],#3,"gt",-1) or not (2*-3)`: {Root: &ExprBinary{Op: "or",
+			X: &ExprFunction{Name: "count", Args: []ExprNode{
+				&ExprQuery{Host: "*", Key: "net.if.in[*]", Filter: `group="Linux servers" and tag="a]"`},
+				&ExprConstant{"#3"}, &ExprString{"gt"}, &ExprUnary{Op: "-", X: &ExprConstant{"1"}},
+			}},
+			Y: &ExprUnary{Op: "not", X: &ExprBinary{Op: "*", X: &ExprConstant{"2"}, Y: &ExprUnary{Op: "-", X: &ExprConstant{"3"}}}},
+		}},
+		`find(//log[/var/log/syslog],,"like","a\"b")`: {Root: &ExprFunction{Name: "find", Args: []ExprNode{
+			&ExprQuery{Key: "log[/var/log/syslog]"}, &ExprConstant{""}, &ExprString{"like"}, &ExprString{`a"b`},
+		}}},
+		"{host:system.cpu.load[all,avg1].avg(5m)}>2 & {host:agent.ping.nodata(300)}=1": {Legacy: true, Root: &ExprBinary{Op: "and",
+			X: &ExprBinary{Op: ">",
+				X: &ExprFunction{Name: "avg", Legacy: true, Args: []ExprNode{
+					&ExprQuery{Host: "host", Key: "system.cpu.load[all,avg1]"}, &ExprConstant{"5m"},
+				}},
+				Y: &ExprConstant{"2"},
+			},
+			Y: &ExprBinary{Op: "=",
+				X: &ExprFunction{Name: "nodata", Legacy: true, Args: []ExprNode{
+					&ExprQuery{Host: "host", Key: "agent.ping"}, &ExprConstant{"300"},
+				}},
+				Y: &ExprConstant{"1"},
+			},
+		}},
+		`{Zabbix server:log[/tmp/a.log].str("error, fatal",#2)}#0 and {TRIGGER.VALUE}=0`: {Legacy: true, Root: &ExprBinary{Op: "and",
+			X: &ExprBinary{Op: "<>",
+				X: &ExprFunction{Name: "str", Legacy: true, Args: []ExprNode{
+					&ExprQuery{Host: "Zabbix server", Key: "log[/tmp/a.log]"}, &ExprString{"error, fatal"}, &ExprConstant{"#2"},
+				}},
+				Y: &ExprConstant{"0"},
+			},
+			Y: &ExprBinary{Op: "=", X: &ExprMacro{"{TRIGGER.VALUE}"}, Y: &ExprConstant{"0"}},
+		}},
+		"last(/h/k)>.5 or last(/h/k)<1e3 and last(/h/k)<>1.5E-3": {Root: &ExprBinary{Op: "or",
+			X: &ExprBinary{Op: ">", X: &ExprFunction{Name: "last", Args: []ExprNode{&ExprQuery{Host: "h", Key: "k"}}}, Y: &ExprConstant{".5"}},
+			Y: &ExprBinary{Op: "and",
+				X: &ExprBinary{Op: "<", X: &ExprFunction{Name: "last", Args: []ExprNode{&ExprQuery{Host: "h", Key: "k"}}}, Y: &ExprConstant{"1e3"}},
+				Y: &ExprBinary{Op: "<>", X: &ExprFunction{Name: "last", Args: []ExprNode{&ExprQuery{Host: "h", Key: "k"}}}, Y: &ExprConstant{"1.5E-3"}},
+			},
+		}},
+		"{host:agent.version.diff()}=1": {Legacy: true, Root: &ExprBinary{Op: "=",
+			X: &ExprFunction{Name: "diff", Legacy: true, Args: []ExprNode{&ExprQuery{Host: "host", Key: "agent.version"}}},
+			Y: &ExprConstant{"1"},
+		}},
+	} {
+		expr, err := ParseExpression(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(expr, expected) {
+			t.Errorf("%s:\nexpected %#v\ngot      %#v", s, expected, expr)
+		}
+	}
+
+	for _, s := range []string{
+		"", "last(/host/key", "last(/host/key)=", "(1+2", `find(/host/key,,"like","a)`, "foo", "1 2",
+		"last(host/key)", "last(/host/key[a)", "{host:key.last(0)", "{host:key}", "{host:key[a.last()}",
+		"{13083}>0", "{13083}>0 and {13084}<5", "1.>0", "1e3K>0", ".>0",
+		"{h:[].count()}", "{0:[].count()}>0",
+	} {
+		_, err := ParseExpression(s)
+		if !IsInvalidParams(err) {
+			t.Errorf("%q: expected invalid params error, got %v", s, err)
+		}
+	}
+}
+
+func TestExpressionString(t *testing.T) {
+	for s, expected := range map[string]string{
+		"last(/host/key) = 1":                                 "last(/host/key)=1",
+		"(1 + 2) * 3 - (4 - 5)":                               "(1+2)*3-(4-5)",
+		"1 - 2 - 3":                                           "1-2-3",
+		"not (1 or 2) and -(3 + 4)":                           "not (1 or 2) and -(3+4)",
+		"- -1 + 1 - -2 * - - 3":                               "- -1+1- -2*- -3",
+		"1e+3 - .5K":                                          "1e+3-.5K",
+		`find(/h/k?[tag = "x"], , "like", "a\\b")`:            `find(/h/k?[tag = "x"],,"like","a\\b")`,
+		"{host:key[a, b].last( 0 )} # 1":                      "{host:key[a, b].last(0)}<>1",
+		`{host:log.str("a\"b")}=1 | {$M}>{host:k.min(5m,1d)}`: `{host:log.str("a\"b")}=1 or {$M}>{host:k.min(5m,1d)}`,
+	} {
+		expr, err := ParseExpression(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := expr.String(); actual != expected {
+			t.Errorf("%s: expected %s, got %s", s, expected, actual)
+		}
+		if _, err = ParseExpression(expr.String()); err != nil {
+			t.Errorf("%s: %s", s, err)
+		}
+	}
+}
+
+func TestExpressionReferences(t *testing.T) {
+	expr, err := ParseExpression("last(/a/k1)>0 and min(/b/k2[x],5m)<last(/a/k1,#2) or last(//k3)=1 or sum(/a/k1?[tag=\"t\"],1h)>0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ExprQuery{{Host: "a", Key: "k1"}, {Host: "b", Key: "k2[x]"}, {Key: "k3"}}
+	if refs := expr.References(); !reflect.DeepEqual(refs, expected) {
+		t.Errorf("expected %#v, got %#v", expected, refs)
+	}
+
+	expr.RenameHost("a", "c")
+	s := `last(/c/k1)>0 and min(/b/k2[x],5m)<last(/c/k1,#2) or last(//k3)=1 or sum(/c/k1?[tag="t"],1h)>0`
+	if expr.String() != s {
+		t.Errorf("expected %s, got %s", s, expr.String())
+	}
+
+	expr, err = ParseExpression("{old:k.last()}>{old:k.avg(1h)}-{other:k.last()}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []ExprQuery{{Host: "old", Key: "k"}, {Host: "other", Key: "k"}}
+	if refs := expr.References(); !reflect.DeepEqual(refs, expected) {
+		t.Errorf("expected %#v, got %#v", expected, refs)
+	}
+	expr.RenameHost("old", "new")
+	s = "{new:k.last()}>{new:k.avg(1h)}-{other:k.last()}"
+	if expr.String() != s {
+		t.Errorf("expected %s, got %s", s, expr.String())
+	}
+}
+
+func TestExpressionConvertToNew(t *testing.T) {
+	for s, expected := range map[string]string{
+		"{h:k.last()}>0":                           "last(/h/k)>0",
+		"{h:k.last(0)}>0":                          "last(/h/k)>0",
+		"{h:k.last(#3,1d)}>0":                      "last(/h/k,#3:now-1d)>0",
+		"{h:k.prev()}>0":                           "last(/h/k,#2)>0",
+		"{h:k[a,b].avg(5m,1h)}>{$MAX}":             "avg(/h/k[a,b],5m:now-1h)>{$MAX}",
+		"{h:k.count(10m,5)}>2":                     `count(/h/k,10m,"eq","5")>2`,
+		`{h:k.count(10m,"err")}>2`:                 `count(/h/k,10m,"like","err")>2`,
+		"{h:k.count(10m,5,gt)}>2":                  `count(/h/k,10m,"gt","5")>2`,
+		"{h:k.count(#5)}>2":                        "count(/h/k,#5)>2",
+		"{h:k.nodata(5m)}=1":                       "nodata(/h/k,5m)=1",
+		"{h:k.nodata(5m,strict)}=1":                `nodata(/h/k,5m,"strict")=1`,
+		"{h:k.abschange()}>10":                     "abs(change(/h/k))>10",
+		"{h:k.delta(1h)}>10":                       "max(/h/k,1h)-min(/h/k,1h)>10",
+		"{h:k.diff()}=1":                           "last(/h/k,#1)<>last(/h/k,#2)=1",
+		`{h:log.str(error)}=1`:                     `find(/h/log,,"like","error")=1`,
+		`{h:log.iregexp("^a",#2)}=1`:               `find(/h/log,#2,"iregexp","^a")=1`,
+		"{h:k.strlen()}>0":                         "length(last(/h/k))>0",
+		"{h:k.band(#2,12)}=8":                      "bitand(last(/h/k,#2),12)=8",
+		"{h:k.timeleft(1h,,100)}<1h":               "timeleft(/h/k,1h,100)<1h",
+		"{h:k.forecast(1h,,10m,polynomial3)}>0":    `forecast(/h/k,1h,10m,"polynomial3")>0`,
+		`{h:eventlog[System].logsource(^Disk$)}=1`: `logsource(/h/eventlog[System],,"^Disk$")=1`,
+		"{h:k.last()}>0 and {h:k.time()}>080000":   "last(/h/k)>0 and time()>080000",
+		"last(/h/k)>0":                             "last(/h/k)>0",
+	} {
+		expr, err := ParseExpression(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := expr.String()
+		converted, err := expr.ConvertToNew()
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if converted.Legacy {
+			t.Errorf("%s: expected converted expression not to be legacy", s)
+		}
+		if actual := converted.String(); actual != expected {
+			t.Errorf("%s: expected %s, got %s", s, expected, actual)
+		}
+		if expr.String() != original {
+			t.Errorf("%s: original expression modified: %s", s, expr.String())
+		}
+		if _, err = ParseExpression(converted.String()); err != nil {
+			t.Errorf("%s: converted expression doesn't parse: %s", s, err)
+		}
+	}
+
+	expr, err := ParseExpression("{h:k.unknownfunc()}=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = expr.ConvertToNew()
+	if !IsInvalidParams(err) {
+		t.Errorf("expected invalid params error, got %v", err)
+	}
+}
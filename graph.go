@@ -0,0 +1,204 @@
+package zabbix
+
+type (
+	GraphType     int
+	YAxisType     int
+	CalcFunction  int
+	DrawType      int
+	GraphItemType int
+	YAxisSide     int
+)
+
+const (
+	GraphNormal   GraphType = 0
+	GraphStacked  GraphType = 1
+	GraphPie      GraphType = 2
+	GraphExploded GraphType = 3
+
+	YAxisCalculated YAxisType = 0
+	YAxisFixed      YAxisType = 1
+	YAxisItem       YAxisType = 2
+
+	CalcMin     CalcFunction = 1
+	CalcAverage CalcFunction = 2
+	CalcMax     CalcFunction = 4
+	CalcAll     CalcFunction = 7
+	CalcLast    CalcFunction = 9
+
+	DrawLine         DrawType = 0
+	DrawFilledRegion DrawType = 1
+	DrawBoldLine     DrawType = 2
+	DrawDot          DrawType = 3
+	DrawDashedLine   DrawType = 4
+	DrawGradientLine DrawType = 5
+
+	GraphItemSimple GraphItemType = 0
+	GraphItemSum    GraphItemType = 2
+
+	YAxisLeft  YAxisSide = 0
+	YAxisRight YAxisSide = 1
+)
+
+// Colors assigned to graph items by NewGraphFromItems, in order.
+var GraphPalette = []string{
+	"1A7C11", "F63100", "2774A4", "A54F10", "FC6EA3", "6C59DC", "AC8C14", "611F27",
+	"F230E0", "5CCD18", "BB2A02", "5A2B57", "89ABF8", "7EC25C", "274482", "2B5429",
+}
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/graphitem/definitions
+type GraphItem struct {
+	GraphItemId  string        `json:"gitemid,omitempty"`
+	GraphId      string        `json:"graphid,omitempty"`
+	ItemId       string        `json:"itemid"`
+	Color        string        `json:"color"`
+	CalcFunction CalcFunction  `json:"calc_fnc,omitempty"`
+	DrawType     DrawType      `json:"drawtype"`
+	SortOrder    int           `json:"sortorder"`
+	Type         GraphItemType `json:"type"`
+	YAxisSide    YAxisSide     `json:"yaxisside"`
+}
+
+type GraphItems []GraphItem
+
+// https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/definitions
+type Graph struct {
+	GraphId        string    `json:"graphid,omitempty"`
+	Name           string    `json:"name"`
+	Width          int       `json:"width"`
+	Height         int       `json:"height"`
+	GraphType      GraphType `json:"graphtype"`
+	ShowLegend     int       `json:"show_legend"`
+	ShowWorkPeriod int       `json:"show_work_period"`
+	ShowTriggers   int       `json:"show_triggers"`
+	Show3D         int       `json:"show_3d"`
+	PercentLeft    float64   `json:"percent_left"`
+	PercentRight   float64   `json:"percent_right"`
+	YAxisMinType   YAxisType `json:"ymin_type"`
+	YAxisMaxType   YAxisType `json:"ymax_type"`
+	YAxisMin       float64   `json:"yaxismin"`
+	YAxisMax       float64   `json:"yaxismax"`
+	YAxisMinItemId string    `json:"ymin_itemid,omitempty"`
+	YAxisMaxItemId string    `json:"ymax_itemid,omitempty"`
+	TemplateId     string    `json:"templateid,omitempty"`
+
+	// Filled by GraphsGet, required when creating graphs
+	GraphItems GraphItems `json:"gitems,omitempty"`
+}
+
+type Graphs []Graph
+
+// Builds normal 900x200 graph with all items, assigning colors from GraphPalette in order.
+func NewGraphFromItems(name string, items Items) (graph Graph) {
+	graph = Graph{Name: name, Width: 900, Height: 200, ShowLegend: 1, ShowWorkPeriod: 1, ShowTriggers: 1}
+	graph.GraphItems = make(GraphItems, len(items))
+	for i, item := range items {
+		graph.GraphItems[i] = GraphItem{
+			ItemId:       item.ItemId,
+			Color:        GraphPalette[i%len(GraphPalette)],
+			CalcFunction: CalcAverage,
+			SortOrder:    i,
+		}
+	}
+	return
+}
+
+// Wrapper for graph.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/get
+// Graph items are selected by default.
+func (api *API) GraphsGet(params Params) (res Graphs, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectGraphItems"]; !present {
+		params["selectGraphItems"] = "extend"
+	}
+	response, err := api.CallWithError("graph.get", params)
+	if err != nil {
+		return
+	}
+
+	mapsToStructs(response.Result.([]interface{}), &res)
+	return
+}
+
+// Gets graph by Id only if there is exactly 1 matching graph.
+func (api *API) GraphGetById(id string) (res *Graph, err error) {
+	graphs, err := api.GraphsGet(Params{"graphids": id})
+	if err != nil {
+		return
+	}
+
+	if len(graphs) == 1 {
+		res = &graphs[0]
+	} else {
+		e := ExpectedOneResult(len(graphs))
+		err = &e
+	}
+	return
+}
+
+// Gets graphs by host Id.
+func (api *API) GraphsGetByHostId(id string) (res Graphs, err error) {
+	return api.GraphsGet(Params{"hostids": id})
+}
+
+// Wrapper for graph.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/create
+func (api *API) GraphsCreate(graphs Graphs) (err error) {
+	payload, err := toMaps(graphs, "templateid")
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("graph.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	graphids := result["graphids"].([]interface{})
+	for i, id := range graphids {
+		graphs[i].GraphId = id.(string)
+	}
+	return
+}
+
+// Wrapper for graph.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/update
+// Graph items are replaced if set.
+func (api *API) GraphsUpdate(graphs Graphs) (err error) {
+	payload, err := toMaps(graphs, "templateid")
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("graph.update", payload)
+	return
+}
+
+// Wrapper for graph.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/delete
+// Cleans GraphId in all graphs elements if call succeed.
+func (api *API) GraphsDelete(graphs Graphs) (err error) {
+	ids := make([]string, len(graphs))
+	for i, graph := range graphs {
+		ids[i] = graph.GraphId
+	}
+
+	err = api.GraphsDeleteByIds(ids)
+	if err == nil {
+		for i := range graphs {
+			graphs[i].GraphId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for graph.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/graph/delete
+func (api *API) GraphsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("graph.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	graphids := result["graphids"].([]interface{})
+	if len(ids) != len(graphids) {
+		err = &ExpectedMore{len(ids), len(graphids)}
+	}
+	return
+}
@@ -0,0 +1,84 @@
+package zabbix_test
+
+import (
+	. "."
+	"testing"
+)
+
+func CreateGraph(items Items, t *testing.T) *Graph {
+	graphs := Graphs{NewGraphFromItems("Graph for "+items[0].Key, items)}
+	err := getAPI(t).GraphsCreate(graphs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &graphs[0]
+}
+
+func DeleteGraph(graph *Graph, t *testing.T) {
+	err := getAPI(t).GraphsDelete(Graphs{*graph})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewGraphFromItems(t *testing.T) {
+	items := make(Items, len(GraphPalette)+1)
+	for i := range items {
+		items[i].ItemId = string(rune('a' + i))
+	}
+
+	graph := NewGraphFromItems("graph", items)
+	if len(graph.GraphItems) != len(items) {
+		t.Fatalf("Bad graph items: %#v", graph.GraphItems)
+	}
+	for i, gitem := range graph.GraphItems {
+		if gitem.ItemId != items[i].ItemId || gitem.SortOrder != i || gitem.Color != GraphPalette[i%len(GraphPalette)] {
+			t.Errorf("Bad graph item %d: %#v", i, gitem)
+		}
+	}
+}
+
+func TestGraphs(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	app := CreateApplication(host, t)
+	defer DeleteApplication(app, t)
+
+	item := CreateItem(app, t)
+	defer DeleteItem(item, t)
+
+	graph := CreateGraph(Items{*item}, t)
+	if graph.GraphId == "" {
+		t.Errorf("Id is empty: %#v", graph)
+	}
+
+	graph2, err := api.GraphGetById(graph.GraphId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if graph2.Name != graph.Name || len(graph2.GraphItems) != 1 || graph2.GraphItems[0].ItemId != item.ItemId {
+		t.Errorf("Graphs are not equal:\n%#v\n%#v", graph, graph2)
+	}
+
+	graph2.Name = "New name for " + item.Key
+	err = api.GraphsUpdate(Graphs{*graph2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graphs, err := api.GraphsGetByHostId(host.HostId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graphs) != 1 || graphs[0].Name != graph2.Name {
+		t.Errorf("Bad graphs: %#v", graphs)
+	}
+
+	DeleteGraph(graph, t)
+}
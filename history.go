@@ -0,0 +1,47 @@
+package zabbix
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/history/object
+//
+// Clock is Unix timestamp, Ns is nanoseconds part of it.
+type HistoryValue struct {
+	ItemId string `json:"itemid"`
+	Clock  int64  `json:"clock"`
+	Ns     int    `json:"ns"`
+	Value  string `json:"value"`
+}
+
+type History []HistoryValue
+
+// Wrapper for history.get: https://www.zabbix.com/documentation/current/manual/api/reference/history/get
+// Note that "history" param (item value type) defaults to Unsigned on server side.
+func (api *API) HistoryGet(params Params) (res History, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("history.get", params)
+	if err != nil {
+		return
+	}
+
+	mapsToStructs(response.Result.([]interface{}), &res)
+	return
+}
+
+// Gets history of given item, using its value type. Other params (like "time_from", "sortfield" or "limit")
+// are passed as is.
+func (api *API) HistoryGetByItem(item *Item, params Params) (res History, err error) {
+	p := Params{"itemids": item.ItemId, "history": item.ValueType}
+	for k, v := range params {
+		p[k] = v
+	}
+	return api.HistoryGet(p)
+}
+
+// Renders history values through value map (which may be nil) for reports, see ValueMap.Render.
+func (history History) Render(valueMap *ValueMap) (res []string) {
+	res = make([]string, len(history))
+	for i, h := range history {
+		res[i] = valueMap.Render(h.Value)
+	}
+	return
+}
@@ -0,0 +1,52 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestHistoryGet(t *testing.T) {
+	var getParams map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "history.get":
+			getParams = nil
+			json.Unmarshal(params, &getParams)
+			return []map[string]string{
+				{"itemid": "7", "clock": "1600000000", "ns": "500", "value": "1"},
+				{"itemid": "7", "clock": "1600000060", "ns": "0", "value": "0"},
+			}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	history, err := api.HistoryGetByItem(&Item{ItemId: "7", ValueType: Character}, Params{"limit": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedParams := map[string]interface{}{"itemids": "7", "history": float64(Character), "limit": float64(2), "output": "extend"}
+	if !reflect.DeepEqual(getParams, expectedParams) {
+		t.Errorf("Bad params:\n%#v\n%#v", getParams, expectedParams)
+	}
+	expected := History{{"7", 1600000000, 500, "1"}, {"7", 1600000060, 0, "0"}}
+	if !reflect.DeepEqual(history, expected) {
+		t.Errorf("Bad history:\n%#v\n%#v", history, expected)
+	}
+
+	// explicit value type is not replaced
+	if _, err = api.HistoryGetByItem(&Item{ItemId: "7"}, Params{"history": Log}); err != nil {
+		t.Fatal(err)
+	}
+	if getParams["history"] != float64(Log) {
+		t.Errorf("Bad params: %#v", getParams)
+	}
+
+	valueMap := &ValueMap{Mappings: ValueMappings{{Value: "1", NewValue: "Up"}}}
+	if r := history.Render(valueMap); !reflect.DeepEqual(r, []string{"Up (1)", "0"}) {
+		t.Errorf("Bad rendered values: %#v", r)
+	}
+}
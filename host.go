@@ -1,12 +1,9 @@
 package zabbix
 
-import (
-	"github.com/AlekSi/reflector"
-)
-
 type (
-	AvailableType int
-	StatusType    int
+	AvailableType   int
+	StatusType      int
+	MonitoredByType int
 )
 
 const (
@@ -15,24 +12,62 @@ const (
 
 	Monitored   StatusType = 0
 	Unmonitored StatusType = 1
+
+	MonitoredByServer     MonitoredByType = 0
+	MonitoredByProxy      MonitoredByType = 1
+	MonitoredByProxyGroup MonitoredByType = 2
 )
 
 // https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/definitions
+//
+// ProxyId is sent as proxy_hostid before Zabbix 7.0. MonitoredBy and ProxyGroupId are supported since 7.0;
+// MonitoredBy is set to MonitoredByProxy there if only ProxyId is set.
+//
+// TLSConnect is one of TLSNoEncryption, TLSPSK and TLSCertificate; TLSAccept is a combination of them.
+// TLS settings are supported since Zabbix 3.0; TLSPSK is write-only, TLSPSKIdentity is write-only since 5.4.
+//
+// Inventory is filled by HostsGet with "selectInventory" param.
+// InventoryMode is read only if host.get returns it (since Zabbix 4.4) or Inventory is selected.
+// As InventoryManual is zero value, it is sent only if Inventory is set.
 type Host struct {
-	HostId    string        `json:"hostid,omitempty"`
-	Host      string        `json:"host"`
-	Available AvailableType `json:"available"`
-	Error     string        `json:"error"`
-	Name      string        `json:"name"`
-	Status    StatusType    `json:"status"`
+	HostId       string          `json:"hostid,omitempty"`
+	Host         string          `json:"host"`
+	Available    AvailableType   `json:"available"`
+	Error        string          `json:"error"`
+	Name         string          `json:"name"`
+	Status       StatusType      `json:"status"`
+	MonitoredBy  MonitoredByType `json:"monitored_by,omitempty"`
+	ProxyId      string          `json:"proxyid,omitempty"`
+	ProxyGroupId string          `json:"proxy_groupid,omitempty"`
+
+	TLSConnect     TLSMode `json:"tls_connect,omitempty"`
+	TLSAccept      TLSMode `json:"tls_accept,omitempty"`
+	TLSIssuer      string  `json:"tls_issuer,omitempty"`
+	TLSSubject     string  `json:"tls_subject,omitempty"`
+	TLSPSKIdentity string  `json:"tls_psk_identity,omitempty"`
+	TLSPSK         string  `json:"tls_psk,omitempty"`
+
+	InventoryMode InventoryMode  `json:"inventory_mode,omitempty"`
+	Inventory     *HostInventory `json:"inventory,omitempty"`
+
+	// Filled by HostsGet with "selectTags" param; supported since Zabbix 4.2
+	Tags Tags `json:"tags,omitempty"`
 
-	// Fields below used only when creating hosts
-	GroupIds   HostGroupIds   `json:"groups,omitempty"`
-	Interfaces HostInterfaces `json:"interfaces,omitempty"`
+	// Fields below used only when creating hosts; they are filled by HostsGet with "selectGroups"
+	// (or "selectHostGroups" since Zabbix 6.2), "selectInterfaces" and "selectParentTemplates" params
+	GroupIds    HostGroupIds   `json:"groups,omitempty"`
+	Interfaces  HostInterfaces `json:"interfaces,omitempty"`
+	TemplateIds TemplateIds    `json:"templates,omitempty"`
 }
 
 type Hosts []Host
 
+type HostId struct {
+	HostId string `json:"hostid"`
+}
+
+type HostIds []HostId
+
 // Wrapper for host.get: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/get
 func (api *API) HostsGet(params Params) (res Hosts, err error) {
 	if _, present := params["output"]; !present {
@@ -43,7 +78,89 @@ func (api *API) HostsGet(params Params) (res Hosts, err error) {
 		return
 	}
 
-	reflector.MapsToStructs2(response.Result.([]interface{}), &res, reflector.Strconv, "json")
+	maps := response.Result.([]interface{})
+	for _, m := range maps {
+		m := m.(map[string]interface{})
+		if proxyId, present := m["proxy_hostid"]; present {
+			m["proxyid"] = proxyId
+			delete(m, "proxy_hostid")
+		}
+		for from, to := range map[string]string{"hostgroups": "groups", "parentTemplates": "templates"} {
+			if v, present := m[from]; present {
+				m[to] = v
+				delete(m, from)
+			}
+		}
+		for _, f := range []string{"proxyid", "proxy_groupid"} {
+			if m[f] == "0" {
+				delete(m, f)
+			}
+		}
+		normalizeHostInventory(m)
+	}
+	mapsToStructs(maps, &res)
+	return
+}
+
+// Converts hosts to payload for host.create and host.update according to server version.
+// Version is detected only if hosts use fields which depend on it.
+func (api *API) hostsPayload(hosts Hosts) (res []map[string]interface{}, err error) {
+	var v ServerVersion
+	for _, host := range hosts {
+		if host.ProxyId != "" || host.ProxyGroupId != "" || host.MonitoredBy != MonitoredByServer ||
+			host.InventoryMode != InventoryManual || host.Inventory != nil || len(host.Tags) > 0 ||
+			host.TLSConnect != 0 || host.TLSAccept != 0 || host.TLSPSKIdentity != "" || host.TLSPSK != "" {
+			v, err = api.ServerVersion()
+			if err != nil {
+				return
+			}
+			break
+		}
+	}
+
+	res, err = toMaps(hosts, "available", "error")
+	if err != nil {
+		return
+	}
+
+	for i, host := range hosts {
+		m := res[i]
+		err = checkTLS(v, "Host", host.TLSConnect, host.TLSAccept, host.TLSPSKIdentity, host.TLSPSK)
+		if err != nil {
+			return
+		}
+		if len(host.Tags) > 0 && !v.AtLeast(4, 2) {
+			err = &UnsupportedError{"Host Tags", v}
+			return
+		}
+		if host.Inventory != nil || host.InventoryMode != InventoryManual {
+			m["inventory_mode"] = host.InventoryMode
+			if !v.AtLeast(4, 4) {
+				inventory, _ := m["inventory"].(map[string]interface{})
+				if inventory == nil {
+					inventory = make(map[string]interface{})
+				}
+				inventory["inventory_mode"] = host.InventoryMode
+				m["inventory"] = inventory
+				delete(m, "inventory_mode")
+			}
+		}
+		if v.AtLeast(7, 0) {
+			if host.ProxyId != "" && host.MonitoredBy == MonitoredByServer {
+				m["monitored_by"] = MonitoredByProxy
+			}
+			continue
+		}
+		if host.ProxyGroupId != "" || host.MonitoredBy == MonitoredByProxyGroup {
+			err = &UnsupportedError{"Host ProxyGroupId", v}
+			return
+		}
+		delete(m, "monitored_by")
+		if host.ProxyId != "" {
+			m["proxy_hostid"] = host.ProxyId
+			delete(m, "proxyid")
+		}
+	}
 	return
 }
 
@@ -93,9 +210,87 @@ func (api *API) HostGetByHost(host string) (res *Host, err error) {
 	return
 }
 
+// Gets host by Host, creating it if it doesn't exist.
+// If it exists and its Name, Status or groups differ from given ones, only those fields are updated.
+// Empty Name, zero Status (Monitored) and nil GroupIds mean "don't care", so existing unmonitored host
+// is not enabled; use HostsUpdate for that. Interfaces are left intact.
+func (api *API) HostEnsure(host Host) (res *Host, err error) {
+	params := Params{"filter": map[string]string{"host": host.Host}}
+	if host.GroupIds != nil {
+		v, err := api.ServerVersion()
+		if err != nil {
+			return nil, err
+		}
+		params[selectHostGroups(v)] = []string{"groupid"}
+	}
+	hosts, err := api.HostsGet(params)
+	if err == nil && len(hosts) != 1 {
+		e := ExpectedOneResult(len(hosts))
+		err = &e
+	}
+	if err == nil {
+		res = &hosts[0]
+		if !host.differs(res) {
+			return
+		}
+
+		payload := Params{"hostid": res.HostId}
+		if host.Name != "" {
+			payload["name"] = host.Name
+		}
+		if host.Status != Monitored {
+			payload["status"] = host.Status
+		}
+		if host.GroupIds != nil {
+			payload["groups"] = host.GroupIds
+		}
+		_, err = api.CallWithError("host.update", payload)
+		if err != nil {
+			return nil, err
+		}
+		return api.HostGetById(res.HostId)
+	}
+	if !IsNotFound(err) {
+		return
+	}
+
+	hosts = Hosts{host}
+	err = api.HostsCreate(hosts)
+	if err != nil {
+		return nil, err
+	}
+	res = &hosts[0]
+	return
+}
+
+// Reports whether Name, Status or groups of desired host differ from existing one.
+// Empty Name, zero Status and nil GroupIds mean "don't care"; groups are compared regardless of order.
+func (host *Host) differs(existing *Host) bool {
+	if host.GroupIds != nil {
+		if len(host.GroupIds) != len(existing.GroupIds) {
+			return true
+		}
+		ids := make(map[string]bool, len(existing.GroupIds))
+		for _, g := range existing.GroupIds {
+			ids[g.GroupId] = true
+		}
+		for _, g := range host.GroupIds {
+			if !ids[g.GroupId] {
+				return true
+			}
+		}
+	}
+	return (host.Name != "" && host.Name != existing.Name) ||
+		(host.Status != Monitored && host.Status != existing.Status)
+}
+
 // Wrapper for host.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/create
 func (api *API) HostsCreate(hosts Hosts) (err error) {
-	response, err := api.CallWithError("host.create", hosts)
+	payload, err := api.hostsPayload(hosts)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("host.create", payload)
 	if err != nil {
 		return
 	}
@@ -108,6 +303,16 @@ func (api *API) HostsCreate(hosts Hosts) (err error) {
 	return
 }
 
+// Wrapper for host.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/update
+func (api *API) HostsUpdate(hosts Hosts) (err error) {
+	payload, err := api.hostsPayload(hosts)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("host.update", payload)
+	return
+}
+
 // Wrapper for host.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/host/delete
 // Cleans HostId in all hosts elements if call succeed.
 func (api *API) HostsDelete(hosts Hosts) (err error) {
@@ -144,3 +349,259 @@ func (api *API) HostsDeleteByIds(ids []string) (err error) {
 	}
 	return
 }
+
+// Moves hosts to proxy with given Id (or to server if id is empty) using host.massupdate.
+// Sets ProxyId in all hosts elements if call succeed.
+func (api *API) HostsMoveToProxy(hosts Hosts, proxyId string) (err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+
+	params := Params{"hosts": hostIds(hosts)}
+	switch {
+	case v.AtLeast(7, 0) && proxyId == "":
+		params["monitored_by"] = MonitoredByServer
+	case v.AtLeast(7, 0):
+		params["monitored_by"] = MonitoredByProxy
+		params["proxyid"] = proxyId
+	case proxyId == "":
+		params["proxy_hostid"] = "0"
+	default:
+		params["proxy_hostid"] = proxyId
+	}
+	_, err = api.CallWithError("host.massupdate", params)
+	if err == nil {
+		for i := range hosts {
+			hosts[i].ProxyId = proxyId
+			hosts[i].ProxyGroupId = ""
+			hosts[i].MonitoredBy = MonitoredByServer
+			if v.AtLeast(7, 0) && proxyId != "" {
+				hosts[i].MonitoredBy = MonitoredByProxy
+			}
+		}
+	}
+	return
+}
+
+// Moves hosts to proxy group with given Id using host.massupdate. Supported since Zabbix 7.0.
+// Sets ProxyGroupId in all hosts elements if call succeed.
+func (api *API) HostsMoveToProxyGroup(hosts Hosts, proxyGroupId string) (err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	if !v.AtLeast(7, 0) {
+		err = &UnsupportedError{"Proxy groups", v}
+		return
+	}
+
+	params := Params{"hosts": hostIds(hosts), "monitored_by": MonitoredByProxyGroup, "proxy_groupid": proxyGroupId}
+	_, err = api.CallWithError("host.massupdate", params)
+	if err == nil {
+		for i := range hosts {
+			hosts[i].ProxyId = ""
+			hosts[i].ProxyGroupId = proxyGroupId
+			hosts[i].MonitoredBy = MonitoredByProxyGroup
+		}
+	}
+	return
+}
+
+// Returns host.get param for selecting host groups: "selectGroups" was replaced by "selectHostGroups" in Zabbix 6.2.
+func selectHostGroups(v ServerVersion) string {
+	if v.AtLeast(6, 2) {
+		return "selectHostGroups"
+	}
+	return "selectGroups"
+}
+
+func hostIds(hosts Hosts) (res HostIds) {
+	res = make(HostIds, len(hosts))
+	for i, host := range hosts {
+		res[i] = HostId{host.HostId}
+	}
+	return
+}
+
+// Creates a copy of host with given Id: newHost is created with source host's groups, interfaces and templates
+// (unless set in newHost), then source host's own (not inherited) applications (before Zabbix 5.4), value maps
+// (since Zabbix 5.4, where they belong to host) and items are created for it, with InterfaceId, ApplicationIds and
+// ValueMapId pointing to the new objects. Interfaces are matched by type, main flag and address.
+// Discovered items and applications are not copied.
+// If any step after host creation fails, new host is deleted and original error is returned.
+// If deleting fails too, *RollbackError is returned together with new host.
+func (api *API) HostClone(srcHostId string, newHost Host) (res *Host, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+
+	params := Params{"hostids": srcHostId, "selectInterfaces": "extend", "selectParentTemplates": []string{"templateid"},
+		selectHostGroups(v): []string{"groupid"}}
+	hosts, err := api.HostsGet(params)
+	if err != nil {
+		return
+	}
+	if len(hosts) != 1 {
+		e := ExpectedOneResult(len(hosts))
+		err = &e
+		return
+	}
+	src := hosts[0]
+
+	newHost.HostId = ""
+	if len(newHost.GroupIds) == 0 {
+		newHost.GroupIds = src.GroupIds
+	}
+	if len(newHost.Interfaces) == 0 {
+		newHost.Interfaces = make(HostInterfaces, len(src.Interfaces))
+		for i, iface := range src.Interfaces {
+			iface.InterfaceId = ""
+			newHost.Interfaces[i] = iface
+		}
+	}
+	if len(newHost.TemplateIds) == 0 {
+		newHost.TemplateIds = src.TemplateIds
+	}
+
+	var apps Applications
+	if !v.AtLeast(5, 4) {
+		apps, err = api.ApplicationsGet(Params{"hostids": srcHostId, "inherited": false, "filter": map[string]interface{}{"flags": 0}})
+		if err != nil {
+			return
+		}
+	}
+	params = Params{"hostids": srcHostId, "inherited": false, "filter": map[string]interface{}{"flags": 0}}
+	if v.AtLeast(5, 4) {
+		params["selectTags"] = "extend"
+	} else {
+		params["selectApplications"] = []string{"applicationid"}
+	}
+	items, err := api.ItemsGet(params)
+	if err != nil {
+		return
+	}
+	var valueMaps ValueMaps
+	if v.AtLeast(5, 4) {
+		valueMaps, err = api.ValueMapsGetByHostId(srcHostId)
+		if err != nil {
+			return
+		}
+	}
+
+	hosts = Hosts{newHost}
+	err = api.HostsCreate(hosts)
+	if err != nil {
+		return
+	}
+	res = &hosts[0]
+
+	err = api.hostCloneObjects(&src, res, apps, valueMaps, items)
+	if err != nil {
+		if rollbackErr := api.HostsDeleteByIds([]string{res.HostId}); rollbackErr != nil {
+			err = &RollbackError{Err: err, RollbackErr: rollbackErr}
+			return
+		}
+		res = nil
+	}
+	return
+}
+
+// Creates copies of source host's applications, value maps and items for new host.
+// ValueMapId of items is remapped only if valueMaps are given (since Zabbix 5.4), and cleared if it points
+// to value map of other host.
+func (api *API) hostCloneObjects(src, dst *Host, apps Applications, valueMaps ValueMaps, items Items) (err error) {
+	created, err := api.HostsGet(Params{"hostids": dst.HostId, "selectInterfaces": "extend"})
+	if err != nil {
+		return
+	}
+	if len(created) != 1 {
+		e := ExpectedOneResult(len(created))
+		err = &e
+		return
+	}
+	interfaceIds := mapInterfaces(src.Interfaces, created[0].Interfaces)
+
+	appIds := make(map[string]string, len(apps))
+	if len(apps) > 0 {
+		newApps := make(Applications, len(apps))
+		for i, app := range apps {
+			newApps[i] = Application{HostId: dst.HostId, Name: app.Name}
+		}
+		err = api.ApplicationsCreate(newApps)
+		if err != nil {
+			return
+		}
+		for i, app := range apps {
+			appIds[app.ApplicationId] = newApps[i].ApplicationId
+		}
+	}
+
+	var valueMapIds map[string]string
+	if valueMaps != nil {
+		valueMapIds = make(map[string]string, len(valueMaps))
+	}
+	if len(valueMaps) > 0 {
+		newValueMaps := make(ValueMaps, len(valueMaps))
+		for i, vm := range valueMaps {
+			newValueMaps[i] = ValueMap{HostId: dst.HostId, Name: vm.Name, Mappings: vm.Mappings}
+		}
+		err = api.ValueMapsCreate(newValueMaps)
+		if err != nil {
+			return
+		}
+		for i, vm := range valueMaps {
+			valueMapIds[vm.ValueMapId] = newValueMaps[i].ValueMapId
+		}
+	}
+
+	if len(items) == 0 {
+		return
+	}
+	newItems := make(Items, len(items))
+	for i, item := range items {
+		item.ItemId = ""
+		item.Error = ""
+		item.HostId = dst.HostId
+		item.InterfaceId = interfaceIds[item.InterfaceId]
+		if valueMapIds != nil {
+			item.ValueMapId = valueMapIds[item.ValueMapId]
+		}
+		if item.ApplicationIds != nil {
+			ids := make([]string, 0, len(item.ApplicationIds))
+			for _, id := range item.ApplicationIds {
+				if newId := appIds[id]; newId != "" {
+					ids = append(ids, newId)
+				}
+			}
+			item.ApplicationIds = ids
+		}
+		newItems[i] = item
+	}
+	return api.ItemsCreate(newItems)
+}
+
+// Maps Ids of source interfaces to Ids of new ones with the same type, main flag and address,
+// falling back to the same type and main flag.
+func mapInterfaces(src, dst HostInterfaces) (res map[string]string) {
+	res = make(map[string]string, len(src))
+	for _, s := range src {
+		for _, d := range dst {
+			if s.Type == d.Type && s.Main == d.Main && s.IP == d.IP && s.DNS == d.DNS && s.Port == d.Port {
+				res[s.InterfaceId] = d.InterfaceId
+				break
+			}
+		}
+		if res[s.InterfaceId] != "" {
+			continue
+		}
+		for _, d := range dst {
+			if s.Type == d.Type && s.Main == d.Main {
+				res[s.InterfaceId] = d.InterfaceId
+				break
+			}
+		}
+	}
+	return
+}
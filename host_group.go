@@ -58,9 +58,45 @@ func (api *API) HostGroupGetById(id string) (res *HostGroup, err error) {
 	return
 }
 
+// Gets host group by name only if there is exactly 1 matching host group.
+func (api *API) HostGroupGetByName(name string) (res *HostGroup, err error) {
+	groups, err := api.HostGroupsGet(Params{"filter": map[string]string{"name": name}})
+	if err != nil {
+		return
+	}
+
+	if len(groups) == 1 {
+		res = &groups[0]
+	} else {
+		e := ExpectedOneResult(len(groups))
+		err = &e
+	}
+	return
+}
+
+// Gets host group by name, creating it if it doesn't exist.
+func (api *API) HostGroupEnsure(name string) (res *HostGroup, err error) {
+	res, err = api.HostGroupGetByName(name)
+	if !IsNotFound(err) {
+		return
+	}
+
+	groups := HostGroups{{Name: name}}
+	err = api.HostGroupsCreate(groups)
+	if err != nil {
+		return nil, err
+	}
+	res = &groups[0]
+	return
+}
+
 // Wrapper for hostgroup.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostgroup/create
 func (api *API) HostGroupsCreate(hostGroups HostGroups) (err error) {
-	response, err := api.CallWithError("hostgroup.create", hostGroups)
+	payload, err := toMaps(hostGroups, "internal")
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("hostgroup.create", payload)
 	if err != nil {
 		return
 	}
@@ -73,6 +109,16 @@ func (api *API) HostGroupsCreate(hostGroups HostGroups) (err error) {
 	return
 }
 
+// Wrapper for hostgroup.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostgroup/update
+func (api *API) HostGroupsUpdate(hostGroups HostGroups) (err error) {
+	payload, err := toMaps(hostGroups, "internal")
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("hostgroup.update", payload)
+	return
+}
+
 // Wrapper for hostgroup.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostgroup/delete
 // Cleans GroupId in all hostGroups elements if call succeed.
 func (api *API) HostGroupsDelete(hostGroups HostGroups) (err error) {
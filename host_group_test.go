@@ -63,3 +63,25 @@ func TestHostGroups(t *testing.T) {
 		t.Errorf("Error deleting group.\nOld groups: %#v\nNew groups: %#v", groups, groups2)
 	}
 }
+
+func TestHostGroupEnsure(t *testing.T) {
+	api := getAPI(t)
+
+	name := fmt.Sprintf("zabbix-testing-%d", rand.Int())
+	hostGroup, err := api.HostGroupEnsure(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteHostGroup(hostGroup, t)
+	if hostGroup.GroupId == "" || hostGroup.Name != name {
+		t.Errorf("Bad group: %#v", hostGroup)
+	}
+
+	hostGroup2, err := api.HostGroupEnsure(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostGroup2.GroupId != hostGroup.GroupId {
+		t.Errorf("Groups are not equal:\n%#v\n%#v", hostGroup, hostGroup2)
+	}
+}
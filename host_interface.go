@@ -12,7 +12,11 @@ const (
 )
 
 // https://www.zabbix.com/documentation/2.0/manual/appendix/api/hostinterface/definitions
+//
+// InterfaceId is filled by HostsGet with "selectInterfaces" param.
 type HostInterface struct {
+	InterfaceId string `json:"interfaceid,omitempty"`
+
 	DNS   string        `json:"dns"`
 	IP    string        `json:"ip"`
 	Main  int           `json:"main"`
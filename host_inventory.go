@@ -0,0 +1,125 @@
+package zabbix
+
+type (
+	InventoryMode int
+)
+
+const (
+	InventoryDisabled  InventoryMode = -1
+	InventoryManual    InventoryMode = 0
+	InventoryAutomatic InventoryMode = 1
+)
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/host/object#host-inventory
+//
+// Empty fields are omitted on create and update, so they are left intact.
+type HostInventory struct {
+	Type         string `json:"type,omitempty"`
+	TypeFull     string `json:"type_full,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Alias        string `json:"alias,omitempty"`
+	OS           string `json:"os,omitempty"`
+	OSFull       string `json:"os_full,omitempty"`
+	OSShort      string `json:"os_short,omitempty"`
+	SerialNoA    string `json:"serialno_a,omitempty"`
+	SerialNoB    string `json:"serialno_b,omitempty"`
+	Tag          string `json:"tag,omitempty"`
+	AssetTag     string `json:"asset_tag,omitempty"`
+	MACAddressA  string `json:"macaddress_a,omitempty"`
+	MACAddressB  string `json:"macaddress_b,omitempty"`
+	Hardware     string `json:"hardware,omitempty"`
+	HardwareFull string `json:"hardware_full,omitempty"`
+	Software     string `json:"software,omitempty"`
+	SoftwareFull string `json:"software_full,omitempty"`
+	SoftwareAppA string `json:"software_app_a,omitempty"`
+	SoftwareAppB string `json:"software_app_b,omitempty"`
+	SoftwareAppC string `json:"software_app_c,omitempty"`
+	SoftwareAppD string `json:"software_app_d,omitempty"`
+	SoftwareAppE string `json:"software_app_e,omitempty"`
+	Contact      string `json:"contact,omitempty"`
+	Location     string `json:"location,omitempty"`
+	LocationLat  string `json:"location_lat,omitempty"`
+	LocationLon  string `json:"location_lon,omitempty"`
+	Notes        string `json:"notes,omitempty"`
+	Chassis      string `json:"chassis,omitempty"`
+	Model        string `json:"model,omitempty"`
+	HWArch       string `json:"hw_arch,omitempty"`
+	Vendor       string `json:"vendor,omitempty"`
+
+	ContractNumber   string `json:"contract_number,omitempty"`
+	InstallerName    string `json:"installer_name,omitempty"`
+	DeploymentStatus string `json:"deployment_status,omitempty"`
+	URLA             string `json:"url_a,omitempty"`
+	URLB             string `json:"url_b,omitempty"`
+	URLC             string `json:"url_c,omitempty"`
+
+	HostNetworks string `json:"host_networks,omitempty"`
+	HostNetmask  string `json:"host_netmask,omitempty"`
+	HostRouter   string `json:"host_router,omitempty"`
+	OOBIP        string `json:"oob_ip,omitempty"`
+	OOBNetmask   string `json:"oob_netmask,omitempty"`
+	OOBRouter    string `json:"oob_router,omitempty"`
+
+	DateHWPurchase string `json:"date_hw_purchase,omitempty"`
+	DateHWInstall  string `json:"date_hw_install,omitempty"`
+	DateHWExpiry   string `json:"date_hw_expiry,omitempty"`
+	DateHWDecomm   string `json:"date_hw_decomm,omitempty"`
+
+	SiteAddressA string `json:"site_address_a,omitempty"`
+	SiteAddressB string `json:"site_address_b,omitempty"`
+	SiteAddressC string `json:"site_address_c,omitempty"`
+	SiteCity     string `json:"site_city,omitempty"`
+	SiteState    string `json:"site_state,omitempty"`
+	SiteCountry  string `json:"site_country,omitempty"`
+	SiteZip      string `json:"site_zip,omitempty"`
+	SiteRack     string `json:"site_rack,omitempty"`
+	SiteNotes    string `json:"site_notes,omitempty"`
+
+	POC1Name   string `json:"poc_1_name,omitempty"`
+	POC1Email  string `json:"poc_1_email,omitempty"`
+	POC1PhoneA string `json:"poc_1_phone_a,omitempty"`
+	POC1PhoneB string `json:"poc_1_phone_b,omitempty"`
+	POC1Cell   string `json:"poc_1_cell,omitempty"`
+	POC1Screen string `json:"poc_1_screen,omitempty"`
+	POC1Notes  string `json:"poc_1_notes,omitempty"`
+	POC2Name   string `json:"poc_2_name,omitempty"`
+	POC2Email  string `json:"poc_2_email,omitempty"`
+	POC2PhoneA string `json:"poc_2_phone_a,omitempty"`
+	POC2PhoneB string `json:"poc_2_phone_b,omitempty"`
+	POC2Cell   string `json:"poc_2_cell,omitempty"`
+	POC2Screen string `json:"poc_2_screen,omitempty"`
+	POC2Notes  string `json:"poc_2_notes,omitempty"`
+}
+
+// Moves inventory_mode from host inventory object (where it is before Zabbix 4.4) to host object.
+// Host inventory object returned as empty array means disabled inventory.
+func normalizeHostInventory(m map[string]interface{}) {
+	inventory, present := m["inventory"]
+	if !present {
+		return
+	}
+	if inv, ok := inventory.(map[string]interface{}); ok {
+		if mode, present := inv["inventory_mode"]; present {
+			if _, present = m["inventory_mode"]; !present {
+				m["inventory_mode"] = mode
+			}
+		}
+		delete(inv, "inventory_mode")
+		delete(inv, "hostid")
+		return
+	}
+	if _, present = m["inventory_mode"]; !present {
+		m["inventory_mode"] = "-1"
+	}
+	delete(m, "inventory")
+}
+
+// Gets hosts with inventory fields matching all non-empty fields of given inventory
+// (substring match, as "searchInventory" param of host.get). Inventory is selected.
+func (api *API) HostsGetByInventory(inventory HostInventory) (res Hosts, err error) {
+	search, err := toMaps([]HostInventory{inventory})
+	if err != nil {
+		return
+	}
+	return api.HostsGet(Params{"searchInventory": search[0], "selectInventory": "extend"})
+}
@@ -0,0 +1,69 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"testing"
+)
+
+func TestHostInventory(t *testing.T) {
+	version := "4.2.0"
+	var payload []map[string]interface{}
+	var search map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "host.create":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			return map[string]interface{}{"hostids": []string{"1"}}, nil
+		case "host.get":
+			search = nil
+			json.Unmarshal(params, &search)
+			return []map[string]interface{}{
+				{"hostid": "1", "host": "h1", "inventory": map[string]string{"hostid": "1", "inventory_mode": "1", "serialno_a": "SN1", "location": "DC1"}},
+				{"hostid": "2", "host": "h2", "inventory": []interface{}{}},
+			}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	hosts := Hosts{{Host: "h1", InventoryMode: InventoryManual, Inventory: &HostInventory{SerialNoA: "SN1", Location: "DC1"}}}
+	if err := api.HostsCreate(hosts); err != nil {
+		t.Fatal(err)
+	}
+	inventory, _ := payload[0]["inventory"].(map[string]interface{})
+	if _, present := payload[0]["inventory_mode"]; present || inventory["inventory_mode"] != float64(0) || inventory["serialno_a"] != "SN1" {
+		t.Errorf("Bad payload: %#v", payload[0])
+	}
+	if _, present := inventory["os"]; present {
+		t.Errorf("Unexpected empty field in payload: %#v", inventory)
+	}
+
+	hosts, err := api.HostsGetByInventory(HostInventory{Location: "DC1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := search["searchInventory"].(map[string]interface{}); len(s) != 1 || s["location"] != "DC1" {
+		t.Errorf("Bad search: %#v", search)
+	}
+	if hosts[0].InventoryMode != InventoryAutomatic || hosts[0].Inventory == nil || hosts[0].Inventory.SerialNoA != "SN1" {
+		t.Errorf("Bad host: %#v", hosts[0])
+	}
+	if hosts[1].InventoryMode != InventoryDisabled || hosts[1].Inventory != nil {
+		t.Errorf("Bad host: %#v", hosts[1])
+	}
+
+	version = "5.0.0"
+	api = NewAPI(srv.URL)
+	hosts = Hosts{{Host: "h1", InventoryMode: InventoryDisabled}}
+	if err = api.HostsCreate(hosts); err != nil {
+		t.Fatal(err)
+	}
+	if payload[0]["inventory_mode"] != float64(-1) || payload[0]["inventory"] != nil {
+		t.Errorf("Bad payload: %#v", payload[0])
+	}
+}
@@ -2,9 +2,12 @@ package zabbix_test
 
 import (
 	. "."
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -57,6 +60,9 @@ func TestHosts(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	// TLS settings and inventory mode are filled by server since Zabbix 3.0 and 4.4
+	host.TLSConnect, host.TLSAccept = host2.TLSConnect, host2.TLSAccept
+	host.InventoryMode = host2.InventoryMode
 	if !reflect.DeepEqual(host, host2) {
 		t.Errorf("Hosts are not equal:\n%#v\n%#v", host, host2)
 	}
@@ -87,3 +93,230 @@ func TestHosts(t *testing.T) {
 		t.Errorf("Bad hosts: %#v", hosts)
 	}
 }
+
+func TestHostEnsure(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	name := fmt.Sprintf("%s-%d", getHost(), rand.Int())
+	iface := HostInterface{DNS: name, Port: "42", Type: Agent, UseIP: 0, Main: 1}
+	desired := Host{
+		Host:       name,
+		Name:       "Name for " + name,
+		GroupIds:   HostGroupIds{{group.GroupId}},
+		Interfaces: HostInterfaces{iface},
+	}
+	host, err := api.HostEnsure(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteHost(host, t)
+	if host.HostId == "" {
+		t.Errorf("Id is empty: %#v", host)
+	}
+
+	desired.Name = "New name for " + name
+	desired.Status = Unmonitored
+	host2, err := api.HostEnsure(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host2.HostId != host.HostId || host2.Name != desired.Name || host2.Status != Unmonitored {
+		t.Errorf("Host is not updated:\n%#v\n%#v", host, host2)
+	}
+}
+
+func TestHostEnsureUpdate(t *testing.T) {
+	var updates []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return "6.2.0", nil
+		case "host.get":
+			host := map[string]interface{}{"hostid": "10", "host": "h", "name": "Old", "status": "1"}
+			if strings.Contains(string(params), "selectHostGroups") {
+				host["hostgroups"] = []map[string]string{{"groupid": "2"}, {"groupid": "3"}}
+			}
+			return []map[string]interface{}{host}, nil
+		case "host.update":
+			var p map[string]interface{}
+			json.Unmarshal(params, &p)
+			updates = append(updates, p)
+			return map[string]interface{}{"hostids": []string{"10"}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	for _, host := range []Host{{Host: "h"}, {Host: "h", Name: "Old"}, {Host: "h", Status: Unmonitored},
+		{Host: "h", GroupIds: HostGroupIds{{"3"}, {"2"}}}} {
+		if _, err := api.HostEnsure(host); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(updates) != 0 {
+		t.Fatalf("Unexpected updates: %#v", updates)
+	}
+
+	if _, err := api.HostEnsure(Host{Host: "h", Name: "New"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.HostEnsure(Host{Host: "h", GroupIds: HostGroupIds{{"2"}}}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{
+		{"hostid": "10", "name": "New"},
+		{"hostid": "10", "groups": []interface{}{map[string]interface{}{"groupid": "2"}}},
+	}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("Bad updates:\n%#v\n%#v", updates, expected)
+	}
+}
+
+func TestHostClone(t *testing.T) {
+	version := "4.0.0"
+	var hostCreate, appCreate, valueMapCreate, itemCreate []map[string]interface{}
+	var deleted []string
+	failItems, failDelete := false, false
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "host.get":
+			var p map[string]interface{}
+			json.Unmarshal(params, &p)
+			if p["hostids"] == "20" {
+				return []map[string]interface{}{{"hostid": "20", "host": "copy", "interfaces": []map[string]string{
+					{"interfaceid": "201", "type": "1", "main": "1", "ip": "10.0.0.2", "dns": "", "port": "10050", "useip": "1"},
+					{"interfaceid": "202", "type": "2", "main": "1", "ip": "10.0.0.2", "dns": "", "port": "161", "useip": "1"},
+				}}}, nil
+			}
+			return []map[string]interface{}{{"hostid": "10", "host": "src", "name": "Source",
+				"groups":          []map[string]string{{"groupid": "2"}},
+				"parentTemplates": []map[string]string{{"templateid": "100"}},
+				"interfaces": []map[string]string{
+					{"interfaceid": "101", "type": "1", "main": "1", "ip": "10.0.0.1", "dns": "", "port": "10050", "useip": "1"},
+					{"interfaceid": "102", "type": "2", "main": "1", "ip": "10.0.0.1", "dns": "", "port": "161", "useip": "1"},
+				}}}, nil
+		case "host.create":
+			hostCreate = nil
+			json.Unmarshal(params, &hostCreate)
+			return map[string]interface{}{"hostids": []string{"20"}}, nil
+		case "host.delete":
+			if failDelete {
+				return nil, &Error{-32500, "Application error.", "Connection to database lost."}
+			}
+			var p []map[string]string
+			json.Unmarshal(params, &p)
+			deleted = append(deleted, p[0]["hostid"])
+			return map[string]interface{}{"hostids": []string{p[0]["hostid"]}}, nil
+		case "application.get":
+			return []map[string]string{{"applicationid": "51", "hostid": "10", "name": "CPU"}, {"applicationid": "52", "hostid": "10", "name": "Net"}}, nil
+		case "application.create":
+			appCreate = nil
+			json.Unmarshal(params, &appCreate)
+			return map[string]interface{}{"applicationids": []string{"61", "62"}}, nil
+		case "item.get":
+			items := []map[string]interface{}{
+				{"itemid": "71", "hostid": "10", "interfaceid": "101", "key_": "system.cpu.load", "name": "Load", "delay": "{$INTERVAL}",
+					"history": "0", "trends": "0", "type": "0", "value_type": "0", "valuemapid": "91"},
+				{"itemid": "72", "hostid": "10", "interfaceid": "102", "key_": "ifInOctets.1", "name": "In", "delay": "30s;50s/1-5,09:00-18:00",
+					"history": "1h", "trends": "0", "type": "4", "value_type": "3", "valuemapid": "92"},
+			}
+			if version == "4.0.0" {
+				items[0]["applications"] = []map[string]string{{"applicationid": "51"}}
+				items[1]["applications"] = []map[string]string{{"applicationid": "51"}, {"applicationid": "52"}}
+			}
+			return items, nil
+		case "valuemap.get":
+			return []map[string]interface{}{{"valuemapid": "91", "hostid": "10", "name": "State",
+				"mappings": []map[string]string{{"type": "0", "value": "1", "newvalue": "Up"}}}}, nil
+		case "valuemap.create":
+			valueMapCreate = nil
+			json.Unmarshal(params, &valueMapCreate)
+			return map[string]interface{}{"valuemapids": []string{"93"}}, nil
+		case "item.create":
+			if failItems {
+				return nil, &Error{-32602, "Invalid params.", "Item already exists."}
+			}
+			itemCreate = nil
+			json.Unmarshal(params, &itemCreate)
+			return map[string]interface{}{"itemids": []string{"81", "82"}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	host, err := api.HostClone("10", Host{Host: "copy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host.HostId != "20" || host.Host != "copy" {
+		t.Errorf("Bad host: %#v", host)
+	}
+
+	h := hostCreate[0]
+	ifaces := h["interfaces"].([]interface{})
+	if h["host"] != "copy" || len(h["groups"].([]interface{})) != 1 || len(h["templates"].([]interface{})) != 1 ||
+		len(ifaces) != 2 || ifaces[0].(map[string]interface{})["interfaceid"] != nil {
+		t.Errorf("Bad host payload: %#v", h)
+	}
+	if len(appCreate) != 2 || appCreate[0]["hostid"] != "20" || appCreate[1]["name"] != "Net" || appCreate[0]["applicationid"] != nil {
+		t.Errorf("Bad application payload: %#v", appCreate)
+	}
+	if len(itemCreate) != 2 {
+		t.Fatalf("Bad item payload: %#v", itemCreate)
+	}
+	for i, expected := range []struct {
+		interfaceId, delay, history string
+		apps                        []interface{}
+	}{{"201", "{$INTERVAL}", "0", []interface{}{"61"}}, {"202", "30s;50s/1-5,09:00-18:00", "1h", []interface{}{"61", "62"}}} {
+		item := itemCreate[i]
+		if item["hostid"] != "20" || item["itemid"] != nil || item["interfaceid"] != expected.interfaceId ||
+			!reflect.DeepEqual(item["applications"], expected.apps) || item["delay"] != expected.delay ||
+			item["history"] != expected.history || item["trends"] != "0" {
+			t.Errorf("Bad item payload: %#v", item)
+		}
+		if item["valuemapid"] != []string{"91", "92"}[i] {
+			t.Errorf("Global value map should be kept: %#v", item)
+		}
+	}
+	if len(deleted) != 0 {
+		t.Errorf("Unexpected delete: %v", deleted)
+	}
+
+	failItems = true
+	host, err = api.HostClone("10", Host{Host: "copy"})
+	if !IsAlreadyExists(err) || host != nil {
+		t.Errorf("Expected already exists error, got %v, %#v", err, host)
+	}
+	if !reflect.DeepEqual(deleted, []string{"20"}) {
+		t.Errorf("Expected rollback, got %v", deleted)
+	}
+
+	failDelete = true
+	host, err = api.HostClone("10", Host{Host: "copy"})
+	var rollbackErr *RollbackError
+	if !errors.As(err, &rollbackErr) || !IsAlreadyExists(err) || host == nil || host.HostId != "20" {
+		t.Errorf("Expected rollback error with new host, got %v, %#v", err, host)
+	}
+
+	// value maps belong to host since Zabbix 5.4
+	version = "5.4.0"
+	failItems, failDelete = false, false
+	api = NewAPI(srv.URL)
+	if _, err = api.HostClone("10", Host{Host: "copy"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(valueMapCreate) != 1 || valueMapCreate[0]["hostid"] != "20" || valueMapCreate[0]["name"] != "State" ||
+		valueMapCreate[0]["valuemapid"] != nil {
+		t.Errorf("Bad value map payload: %#v", valueMapCreate)
+	}
+	if itemCreate[0]["valuemapid"] != "93" || itemCreate[1]["valuemapid"] != nil || itemCreate[0]["applications"] != nil {
+		t.Errorf("Bad item payload: %#v", itemCreate)
+	}
+}
@@ -0,0 +1,356 @@
+package zabbix
+
+import (
+	"strconv"
+	"strings"
+)
+
+type (
+	HttpTestStatus   int
+	HttpAuthType     int
+	HttpPostType     int
+	HttpRetrieveMode int
+)
+
+const (
+	HttpTestEnabled  HttpTestStatus = 0
+	HttpTestDisabled HttpTestStatus = 1
+
+	HttpAuthNone     HttpAuthType = 0
+	HttpAuthBasic    HttpAuthType = 1
+	HttpAuthNTLM     HttpAuthType = 2
+	HttpAuthKerberos HttpAuthType = 3
+	HttpAuthDigest   HttpAuthType = 4
+
+	HttpPostRaw  HttpPostType = 0
+	HttpPostForm HttpPostType = 1
+
+	RetrieveBody    HttpRetrieveMode = 0
+	RetrieveHeaders HttpRetrieveMode = 1
+	RetrieveBoth    HttpRetrieveMode = 2
+)
+
+// Name and value pair used for web scenario variables, headers, query and post fields.
+type HttpField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HttpFields []HttpField
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/httptest/object#scenario-step
+//
+// Timeout is in seconds. Before Zabbix 3.4 variables and headers are sent as strings;
+// QueryFields, FollowRedirects and RetrieveMode are not supported there. PostFields (form data)
+// are supported since Zabbix 4.0, use Posts for raw data.
+type HttpStep struct {
+	HttpStepId      string           `json:"httpstepid,omitempty"`
+	HttpTestId      string           `json:"httptestid,omitempty"`
+	Name            string           `json:"name"`
+	No              int              `json:"no"`
+	URL             string           `json:"url"`
+	Timeout         int              `json:"timeout,omitempty"`
+	Posts           string           `json:"posts,omitempty"`
+	PostFields      HttpFields       `json:"-"`
+	Required        string           `json:"required"`
+	StatusCodes     string           `json:"status_codes"`
+	FollowRedirects int              `json:"follow_redirects"`
+	RetrieveMode    HttpRetrieveMode `json:"retrieve_mode"`
+	Variables       HttpFields       `json:"variables,omitempty"`
+	Headers         HttpFields       `json:"headers,omitempty"`
+	QueryFields     HttpFields       `json:"query_fields,omitempty"`
+}
+
+type HttpSteps []HttpStep
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/httptest/object
+//
+// Delay is in seconds. ApplicationId is not supported since Zabbix 5.4.
+type HttpTest struct {
+	HttpTestId     string         `json:"httptestid,omitempty"`
+	HostId         string         `json:"hostid"`
+	Name           string         `json:"name"`
+	ApplicationId  string         `json:"applicationid,omitempty"`
+	Delay          int            `json:"delay,omitempty"`
+	Retries        int            `json:"retries,omitempty"`
+	Agent          string         `json:"agent,omitempty"`
+	HttpProxy      string         `json:"http_proxy,omitempty"`
+	Status         HttpTestStatus `json:"status"`
+	Authentication HttpAuthType   `json:"authentication"`
+	HttpUser       string         `json:"http_user,omitempty"`
+	HttpPassword   string         `json:"http_password,omitempty"`
+	VerifyPeer     int            `json:"verify_peer"`
+	VerifyHost     int            `json:"verify_host"`
+	SSLCertFile    string         `json:"ssl_cert_file,omitempty"`
+	SSLKeyFile     string         `json:"ssl_key_file,omitempty"`
+	SSLKeyPassword string         `json:"ssl_key_password,omitempty"`
+	Variables      HttpFields     `json:"variables,omitempty"`
+	Headers        HttpFields     `json:"headers,omitempty"`
+	TemplateId     string         `json:"templateid,omitempty"`
+
+	// Filled by HttpTestsGet, required when creating web scenarios
+	Steps HttpSteps `json:"steps,omitempty"`
+}
+
+type HttpTests []HttpTest
+
+// Formats fields as used before Zabbix 3.4: "{name}=value" lines for variables, "Name: value" lines for headers.
+func (fields HttpFields) legacyString(header bool) string {
+	lines := make([]string, len(fields))
+	for i, f := range fields {
+		if header {
+			lines[i] = f.Name + ": " + f.Value
+		} else {
+			lines[i] = f.Name + "=" + f.Value
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Converts fields in legacy string format to array of objects in place, so they can be decoded into HttpFields.
+func normalizeHttpFields(m map[string]interface{}, key string, header bool) {
+	v, ok := m[key].(string)
+	if !ok {
+		return
+	}
+	sep := "="
+	if header {
+		sep = ":"
+	}
+	var fields []interface{}
+	for _, line := range strings.Split(strings.TrimSpace(v), "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), sep, 2)
+		if len(parts) == 2 {
+			fields = append(fields, map[string]interface{}{
+				"name": strings.TrimSpace(parts[0]), "value": strings.TrimSpace(parts[1]),
+			})
+		}
+	}
+	m[key] = fields
+}
+
+// Wrapper for httptest.get: https://www.zabbix.com/documentation/current/manual/api/reference/httptest/get
+// Steps are selected by default.
+func (api *API) HttpTestsGet(params Params) (res HttpTests, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectSteps"]; !present {
+		params["selectSteps"] = "extend"
+	}
+	response, err := api.CallWithError("httptest.get", params)
+	if err != nil {
+		return
+	}
+
+	maps := response.Result.([]interface{})
+	var postFields [][]HttpFields // form data of steps, PostFields are not decoded by mapsToStructs
+	for _, m := range maps {
+		m, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		normalizeTimeUnits(m, map[string]int{"delay": 1})
+		normalizeHttpFields(m, "variables", false)
+		normalizeHttpFields(m, "headers", true)
+
+		var posts []HttpFields
+		steps, _ := m["steps"].([]interface{})
+		for _, s := range steps {
+			s, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			normalizeTimeUnits(s, map[string]int{"timeout": 1})
+			normalizeHttpFields(s, "variables", false)
+			normalizeHttpFields(s, "headers", true)
+			normalizeHttpFields(s, "query_fields", false)
+			var fields HttpFields
+			if p, ok := s["posts"].([]interface{}); ok {
+				mapsToStructs(p, &fields)
+				delete(s, "posts")
+			}
+			posts = append(posts, fields)
+		}
+		postFields = append(postFields, posts)
+	}
+
+	mapsToStructs(maps, &res)
+	for i := range res {
+		for j := range res[i].Steps {
+			res[i].Steps[j].PostFields = postFields[i][j]
+		}
+	}
+	return
+}
+
+// Gets web scenario by Id only if there is exactly 1 matching web scenario.
+func (api *API) HttpTestGetById(id string) (res *HttpTest, err error) {
+	tests, err := api.HttpTestsGet(Params{"httptestids": id})
+	if err != nil {
+		return
+	}
+
+	if len(tests) == 1 {
+		res = &tests[0]
+	} else {
+		e := ExpectedOneResult(len(tests))
+		err = &e
+	}
+	return
+}
+
+// Gets web scenarios by host Id.
+func (api *API) HttpTestsGetByHostId(id string) (res HttpTests, err error) {
+	return api.HttpTestsGet(Params{"hostids": id})
+}
+
+// Gets items automatically created by server for web scenario: web.test.in, web.test.fail, web.test.error,
+// web.test.time and web.test.rspcode.
+func (api *API) HttpTestItems(test *HttpTest) (res Items, err error) {
+	items, err := api.ItemsGet(Params{
+		"hostids":     test.HostId,
+		"webitems":    true,
+		"search":      map[string]string{"key_": "web.test."},
+		"startSearch": true,
+	})
+	if err != nil {
+		return
+	}
+
+	quoted := strconv.Quote(test.Name)
+	for _, item := range items {
+		if !strings.HasPrefix(item.Key, "web.test.") {
+			continue
+		}
+		i := strings.Index(item.Key, "[")
+		if i < 0 {
+			continue
+		}
+		params := item.Key[i+1:]
+		for _, name := range []string{test.Name, quoted} {
+			if strings.HasPrefix(params, name+",") || strings.HasPrefix(params, name+"]") {
+				res = append(res, item)
+				break
+			}
+		}
+	}
+	return
+}
+
+// Converts web scenarios to payload for httptest.create and httptest.update according to server version.
+func (api *API) httpTestsPayload(tests HttpTests) (res []map[string]interface{}, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	res, err = toMaps(tests, "templateid")
+	if err != nil {
+		return
+	}
+
+	for i, test := range tests {
+		m := res[i]
+		if test.ApplicationId != "" && v.AtLeast(5, 4) {
+			err = &UnsupportedError{"HttpTest ApplicationId", v}
+			return
+		}
+		if test.Delay != 0 {
+			m["delay"] = strconv.Itoa(test.Delay)
+		}
+		if !v.AtLeast(3, 4) {
+			m["variables"] = test.Variables.legacyString(false)
+			m["headers"] = test.Headers.legacyString(true)
+		}
+
+		steps, _ := m["steps"].([]interface{})
+		for j, s := range steps {
+			s := s.(map[string]interface{})
+			step := test.Steps[j]
+			if step.Timeout != 0 {
+				s["timeout"] = strconv.Itoa(step.Timeout)
+			}
+			if len(step.PostFields) > 0 {
+				if !v.AtLeast(4, 0) {
+					err = &UnsupportedError{"HttpStep PostFields", v}
+					return
+				}
+				s["posts"] = step.PostFields
+				s["post_type"] = HttpPostForm
+			}
+			if !v.AtLeast(3, 4) {
+				if len(step.QueryFields) > 0 || step.FollowRedirects != 0 || step.RetrieveMode != RetrieveBody {
+					err = &UnsupportedError{"HttpStep QueryFields, FollowRedirects and RetrieveMode", v}
+					return
+				}
+				delete(s, "follow_redirects")
+				delete(s, "retrieve_mode")
+				s["variables"] = step.Variables.legacyString(false)
+				s["headers"] = step.Headers.legacyString(true)
+			}
+		}
+	}
+	return
+}
+
+// Wrapper for httptest.create: https://www.zabbix.com/documentation/current/manual/api/reference/httptest/create
+func (api *API) HttpTestsCreate(tests HttpTests) (err error) {
+	payload, err := api.httpTestsPayload(tests)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("httptest.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	httptestids := result["httptestids"].([]interface{})
+	for i, id := range httptestids {
+		tests[i].HttpTestId = id.(string)
+	}
+	return
+}
+
+// Wrapper for httptest.update: https://www.zabbix.com/documentation/current/manual/api/reference/httptest/update
+// Steps are replaced if set.
+func (api *API) HttpTestsUpdate(tests HttpTests) (err error) {
+	payload, err := api.httpTestsPayload(tests)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("httptest.update", payload)
+	return
+}
+
+// Wrapper for httptest.delete: https://www.zabbix.com/documentation/current/manual/api/reference/httptest/delete
+// Cleans HttpTestId in all tests elements if call succeed.
+func (api *API) HttpTestsDelete(tests HttpTests) (err error) {
+	ids := make([]string, len(tests))
+	for i, test := range tests {
+		ids[i] = test.HttpTestId
+	}
+
+	err = api.HttpTestsDeleteByIds(ids)
+	if err == nil {
+		for i := range tests {
+			tests[i].HttpTestId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for httptest.delete: https://www.zabbix.com/documentation/current/manual/api/reference/httptest/delete
+func (api *API) HttpTestsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("httptest.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	httptestids := result["httptestids"].([]interface{})
+	if len(ids) != len(httptestids) {
+		err = &ExpectedMore{len(ids), len(httptestids)}
+	}
+	return
+}
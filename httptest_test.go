@@ -0,0 +1,140 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func CreateHttpTest(host *Host, t *testing.T) *HttpTest {
+	tests := HttpTests{{
+		HostId:    host.HostId,
+		Name:      "Scenario for " + host.Host,
+		Delay:     60,
+		Variables: HttpFields{{"{path}", "/"}},
+		Steps: HttpSteps{{
+			Name:            "Index",
+			No:              1,
+			URL:             "http://localhost{path}",
+			StatusCodes:     "200",
+			FollowRedirects: 1,
+			Headers:         HttpFields{{"Accept", "text/html"}},
+		}},
+	}}
+	err := getAPI(t).HttpTestsCreate(tests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tests[0]
+}
+
+func DeleteHttpTest(test *HttpTest, t *testing.T) {
+	err := getAPI(t).HttpTestsDelete(HttpTests{*test})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHttpTestsPayload(t *testing.T) {
+	version := "3.2.0"
+	var created []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "httptest.create":
+			created = nil
+			json.Unmarshal(params, &created)
+			return map[string]interface{}{"httptestids": []string{"1"}}, nil
+		case "httptest.get":
+			return []map[string]interface{}{{
+				"httptestid": "1", "name": "test", "delay": "1m", "variables": "{a}=1\n{b}=2",
+				"steps": []map[string]interface{}{{
+					"httpstepid": "2", "no": "1", "url": "http://localhost/", "timeout": "15s",
+					"headers": []map[string]string{{"name": "Accept", "value": "*/*"}},
+					"posts":   []map[string]string{{"name": "user", "value": "admin"}},
+				}},
+			}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	test := HttpTest{HostId: "1", Name: "test", Delay: 30, Variables: HttpFields{{"{a}", "1"}},
+		Steps: HttpSteps{{Name: "step", No: 1, URL: "http://localhost/", Headers: HttpFields{{"Accept", "*/*"}}}}}
+	if err := api.HttpTestsCreate(HttpTests{test}); err != nil {
+		t.Fatal(err)
+	}
+	step := created[0]["steps"].([]interface{})[0].(map[string]interface{})
+	if created[0]["delay"] != "30" || created[0]["variables"] != "{a}=1" || step["headers"] != "Accept: */*" {
+		t.Errorf("Bad payload: %#v", created[0])
+	}
+	if _, present := step["follow_redirects"]; present {
+		t.Errorf("Unexpected follow_redirects in payload: %#v", step)
+	}
+
+	test.Steps[0].PostFields = HttpFields{{"user", "admin"}}
+	if err := api.HttpTestsCreate(HttpTests{test}); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+
+	version = "4.0.0"
+	api = NewAPI(srv.URL)
+	if err := api.HttpTestsCreate(HttpTests{test}); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(created[0]["steps"])
+	expected := `[{"follow_redirects":0,"headers":[{"name":"Accept","value":"*/*"}],"name":"step","no":1,` +
+		`"post_type":1,"posts":[{"name":"user","value":"admin"}],"required":"","retrieve_mode":0,"status_codes":"","url":"http://localhost/"}]`
+	if string(b) != expected {
+		t.Errorf("Bad steps payload:\n%s\n%s", b, expected)
+	}
+
+	res, err := api.HttpTestGetById("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Delay != 60 || !reflect.DeepEqual(res.Variables, HttpFields{{"{a}", "1"}, {"{b}", "2"}}) || len(res.Steps) != 1 {
+		t.Fatalf("Bad web scenario: %#v", res)
+	}
+	s := res.Steps[0]
+	if s.HttpStepId != "2" || s.Timeout != 15 || !reflect.DeepEqual(s.Headers, HttpFields{{"Accept", "*/*"}}) ||
+		!reflect.DeepEqual(s.PostFields, HttpFields{{"user", "admin"}}) {
+		t.Errorf("Bad step: %#v", s)
+	}
+}
+
+func TestHttpTests(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	test := CreateHttpTest(host, t)
+	if test.HttpTestId == "" {
+		t.Errorf("Id is empty: %#v", test)
+	}
+
+	test2, err := api.HttpTestGetById(test.HttpTestId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if test2.Name != test.Name || len(test2.Steps) != 1 || test2.Steps[0].URL != test.Steps[0].URL {
+		t.Errorf("Web scenarios are not equal:\n%#v\n%#v", test, test2)
+	}
+
+	items, err := api.HttpTestItems(test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) == 0 {
+		t.Errorf("No web scenario items found")
+	}
+
+	DeleteHttpTest(test, t)
+}
@@ -0,0 +1,72 @@
+package zabbix
+
+import (
+	"context"
+	"net/http"
+)
+
+// Invoker performs API call; it is the next step of interceptor chain.
+type Invoker func(ctx context.Context, method string, params interface{}) (response Response, err error)
+
+// Interceptor wraps API calls made with Call, CallWithError and all typed wrappers; see API.SetInterceptors.
+// It may inspect or replace context, method and params before calling next, and inspect or replace
+// response and error after. Use WithRequestHeader to add HTTP request headers.
+// Interceptors are called before response cache, so they see cached responses too.
+type Interceptor func(ctx context.Context, method string, params interface{}, next Invoker) (response Response, err error)
+
+// CallInfo contains details of API call which are not visible in Response; see WithCallInfo.
+type CallInfo struct {
+	Id            int32 // JSON-RPC request id, zero for cached responses
+	BytesSent     int   // size of request body
+	BytesReceived int   // size of response body, zero for cached responses
+	Cached        bool  // response was returned by cache
+}
+
+type (
+	headerKey   struct{}
+	callInfoKey struct{}
+)
+
+// Returns context which makes API fill returned CallInfo for call made with it.
+// Typically used by interceptors for tracing and metrics. If ctx already has CallInfo
+// (registered by outer interceptor), it is returned as is, so all interceptors share it.
+func WithCallInfo(ctx context.Context) (context.Context, *CallInfo) {
+	if info := callInfo(ctx); info != nil {
+		return ctx, info
+	}
+	info := new(CallInfo)
+	return context.WithValue(ctx, callInfoKey{}, info), info
+}
+
+func callInfo(ctx context.Context) *CallInfo {
+	info, _ := ctx.Value(callInfoKey{}).(*CallInfo)
+	return info
+}
+
+// Returns context which adds HTTP header to requests made with it, replacing existing values.
+func WithRequestHeader(ctx context.Context, key, value string) context.Context {
+	h := make(http.Header)
+	if parent, ok := ctx.Value(headerKey{}).(http.Header); ok {
+		for k, v := range parent {
+			h[k] = v
+		}
+	}
+	h.Set(key, value)
+	return context.WithValue(ctx, headerKey{}, h)
+}
+
+func requestHeader(ctx context.Context) http.Header {
+	h, _ := ctx.Value(headerKey{}).(http.Header)
+	return h
+}
+
+// Builds chain of interceptors; the first one is the outermost.
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, method string, params interface{}) (Response, error) {
+			return interceptor(ctx, method, params, next)
+		}
+	}
+	return invoker
+}
@@ -0,0 +1,158 @@
+package zabbix_test
+
+import (
+	. "."
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInterceptors(t *testing.T) {
+	var headers http.Header
+	var params json.RawMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		var req struct {
+			Params json.RawMessage `json:"params"`
+			Id     int32           `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		params = req.Params
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": []interface{}{}})
+	}))
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	if _, err := api.CallWithError("host.get", Params{}); err != nil {
+		t.Fatal(err)
+	}
+	if ua := headers.Get("User-Agent"); ua != DefaultUserAgent || !strings.HasPrefix(ua, "AlekSi-zabbix/") {
+		t.Errorf("Bad User-Agent: %q", ua)
+	}
+
+	var order []string
+	api.SetUserAgent("provisioner/1.0")
+	api.SetInterceptors(
+		func(ctx context.Context, method string, params interface{}, next Invoker) (Response, error) {
+			order = append(order, "outer "+method)
+			ctx = WithRequestHeader(ctx, "X-Request-Id", "42")
+			response, err := next(ctx, method, params)
+			order = append(order, "outer done")
+			return response, err
+		},
+		func(ctx context.Context, method string, params interface{}, next Invoker) (Response, error) {
+			order = append(order, "inner "+method)
+			params.(Params)["limit"] = 1
+			return next(ctx, method, params)
+		},
+	)
+	if _, err := api.HostsGet(Params{}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(order, []string{"outer host.get", "inner host.get", "outer done"}) {
+		t.Errorf("Bad order: %v", order)
+	}
+	if headers.Get("User-Agent") != "provisioner/1.0" || headers.Get("X-Request-Id") != "42" {
+		t.Errorf("Bad headers: %v", headers)
+	}
+	var p map[string]interface{}
+	json.Unmarshal(params, &p)
+	if p["limit"] != float64(1) || p["output"] != "extend" {
+		t.Errorf("Bad params: %s", params)
+	}
+
+	// interceptor may short-circuit call
+	api.SetInterceptors(func(ctx context.Context, method string, params interface{}, next Invoker) (Response, error) {
+		return Response{Error: &Error{-32602, "Invalid params.", "Denied by policy."}}, nil
+	})
+	headers = nil
+	if _, err := api.CallWithError("host.delete", []string{"1"}); !IsInvalidParams(err) {
+		t.Errorf("Expected invalid params error, got %v", err)
+	}
+	if headers != nil {
+		t.Error("Request should not be sent")
+	}
+
+	api.SetInterceptors()
+	api.SetUserAgent("")
+	if _, err := api.CallWithError("host.get", Params{}); err != nil {
+		t.Fatal(err)
+	}
+	if ua := headers.Get("User-Agent"); ua != DefaultUserAgent {
+		t.Errorf("Bad User-Agent: %q", ua)
+	}
+}
+
+func TestCallInfo(t *testing.T) {
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "host.get":
+			return []map[string]string{{"hostid": "1"}, {"hostid": "2"}}, nil
+		case "host.delete":
+			return map[string]interface{}{"hostids": []string{"1"}}, nil
+		}
+		return "7.0.0", nil
+	})
+	defer srv.Close()
+
+	var infos []CallInfo
+	var counts []int
+	api := NewAPI(srv.URL)
+	api.SetCache(NewCache(time.Hour, 0))
+	api.SetInterceptors(func(ctx context.Context, method string, params interface{}, next Invoker) (Response, error) {
+		ctx, info := WithCallInfo(ctx)
+		response, err := next(ctx, method, params)
+		infos = append(infos, *info)
+		n, _ := response.ResultCount()
+		counts = append(counts, n)
+		return response, err
+	})
+
+	for _, method := range []string{"host.get", "host.get", "host.delete"} {
+		if _, err := api.CallWithError(method, Params{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if infos[0].Id == 0 || infos[0].BytesSent == 0 || infos[0].BytesReceived == 0 || infos[0].Cached {
+		t.Errorf("Bad info: %#v", infos[0])
+	}
+	if infos[1] != (CallInfo{Cached: true}) {
+		t.Errorf("Bad info for cached response: %#v", infos[1])
+	}
+	if infos[2].Id <= infos[0].Id {
+		t.Errorf("Bad info: %#v", infos[2])
+	}
+	if !reflect.DeepEqual(counts, []int{2, 2, 1}) {
+		t.Errorf("Bad result counts: %v", counts)
+	}
+}
+
+func TestCallInfoChained(t *testing.T) {
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		return []map[string]string{{"hostid": "1"}}, nil
+	})
+	defer srv.Close()
+
+	infos := make([]*CallInfo, 2)
+	interceptor := func(i int) Interceptor {
+		return func(ctx context.Context, method string, params interface{}, next Invoker) (Response, error) {
+			ctx, infos[i] = WithCallInfo(ctx)
+			return next(ctx, method, params)
+		}
+	}
+	api := NewAPI(srv.URL)
+	api.SetInterceptors(interceptor(0), interceptor(1))
+	if _, err := api.CallWithError("host.get", Params{}); err != nil {
+		t.Fatal(err)
+	}
+	for i, info := range infos {
+		if info.Id == 0 || info.BytesSent == 0 || info.BytesReceived == 0 || info.Cached {
+			t.Errorf("Bad info for interceptor %d: %#v", i, info)
+		}
+	}
+}
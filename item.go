@@ -2,7 +2,8 @@ package zabbix
 
 import (
 	"fmt"
-	"github.com/AlekSi/reflector"
+	"reflect"
+	"strconv"
 )
 
 type (
@@ -48,23 +49,36 @@ const (
 )
 
 // https://www.zabbix.com/documentation/2.0/manual/appendix/api/item/definitions
+//
+// Delay, History and Trends are time units like "1m" or "90d" as used since Zabbix 3.4; they are kept as is,
+// so user macros and flexible intervals like "30s;50s/1-5,09:00-18:00" are preserved. Before Zabbix 3.4
+// they are converted from and to seconds (Delay) and days (History, Trends); values which can't be converted
+// are rejected with *ValidationError when writing. Empty Delay, History and Trends are omitted.
+// DataType and Delta were replaced by preprocessing in Zabbix 3.4; they are converted to equivalent
+// preprocessing steps (placed before Preprocessing) when writing.
 type Item struct {
 	ItemId      string    `json:"itemid,omitempty"`
-	Delay       int       `json:"delay"`
+	Delay       string    `json:"delay,omitempty"`
 	HostId      string    `json:"hostid"`
 	InterfaceId string    `json:"interfaceid,omitempty"`
 	Key         string    `json:"key_"`
 	Name        string    `json:"name"`
 	Type        ItemType  `json:"type"`
 	ValueType   ValueType `json:"value_type"`
-	DataType    DataType  `json:"data_type"`
-	Delta       DeltaType `json:"delta"`
+	DataType    DataType  `json:"data_type,omitempty"`
+	Delta       DeltaType `json:"delta,omitempty"`
 	Description string    `json:"description"`
 	Error       string    `json:"error"`
-	History     int       `json:"history,omitempty"`
-	Trends      int       `json:"trends,omitempty"`
+	History     string    `json:"history,omitempty"`
+	Trends      string    `json:"trends,omitempty"`
+	ValueMapId  string    `json:"valuemapid,omitempty"`
 
-	// Fields below used only when creating applications
+	Preprocessing PreprocessingSteps `json:"preprocessing,omitempty"`
+
+	// Filled by ItemsGet with "selectTags" param; supported since Zabbix 5.4
+	Tags Tags `json:"tags,omitempty"`
+
+	// Fields below used only when creating applications; they are filled by ItemsGet with "selectApplications" param
 	ApplicationIds []string `json:"applications,omitempty"`
 }
 
@@ -88,12 +102,104 @@ func (api *API) ItemsGet(params Params) (res Items, err error) {
 	if _, present := params["output"]; !present {
 		params["output"] = "extend"
 	}
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	if _, present := params["selectPreprocessing"]; !present && v.AtLeast(3, 4) {
+		params["selectPreprocessing"] = "extend"
+	}
 	response, err := api.CallWithError("item.get", params)
 	if err != nil {
 		return
 	}
 
-	reflector.MapsToStructs2(response.Result.([]interface{}), &res, reflector.Strconv, "json")
+	maps := response.Result.([]interface{})
+	for _, m := range maps {
+		m := m.(map[string]interface{})
+		if !v.AtLeast(3, 4) {
+			legacyDays(m, "history", "trends")
+		}
+		if m["valuemapid"] == "0" {
+			delete(m, "valuemapid")
+		}
+		if apps, ok := m["applications"].([]interface{}); ok {
+			ids := make([]interface{}, len(apps))
+			for i, app := range apps {
+				if app, ok := app.(map[string]interface{}); ok {
+					ids[i] = app["applicationid"]
+				}
+			}
+			m["applications"] = ids
+		}
+	}
+	mapsToStructs(maps, &res)
+	return
+}
+
+// Converts items to payload for item.create and item.update according to server version.
+// Version is detected only if items use fields which depend on it. Keys are validated with ValidateItemKey.
+func (api *API) itemsPayload(items Items) (res []map[string]interface{}, err error) {
+	for _, item := range items {
+		if item.Key != "" {
+			err = ValidateItemKey(item.Key)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	var v ServerVersion
+	for _, item := range items {
+		if item.DataType != Decimal || item.Delta != AsIs || item.Delay != "" || item.History != "" || item.Trends != "" ||
+			len(item.ApplicationIds) > 0 || item.Preprocessing != nil || len(item.Tags) > 0 {
+			v, err = api.ServerVersion()
+			if err != nil {
+				return
+			}
+			break
+		}
+	}
+
+	res, err = toMaps(items, "error")
+	if err != nil {
+		return
+	}
+
+	for i, item := range items {
+		m := res[i]
+		if !v.AtLeast(3, 4) && len(item.Preprocessing) > 0 {
+			err = &UnsupportedError{"Item Preprocessing", v}
+			return
+		}
+		if v.AtLeast(3, 4) {
+			delete(m, "data_type")
+			delete(m, "delta")
+			steps := append(LegacyPreprocessing(item.DataType, item.Delta), item.Preprocessing...)
+			if len(steps) > 0 {
+				m["preprocessing"], err = preprocessingPayload(steps, v)
+				if err != nil {
+					return
+				}
+			} else if item.Preprocessing != nil {
+				// explicitly remove all steps on update
+				m["preprocessing"] = []interface{}{}
+			}
+		} else {
+			err = legacyTimeUnits(m, map[string]string{"delay": item.Delay, "history": item.History, "trends": item.Trends}, v)
+			if err != nil {
+				return
+			}
+		}
+		if !v.AtLeast(5, 4) && len(item.Tags) > 0 {
+			err = &UnsupportedError{"Item Tags", v}
+			return
+		}
+		if v.AtLeast(5, 4) && len(item.ApplicationIds) > 0 {
+			err = &UnsupportedError{"Item ApplicationIds", v}
+			return
+		}
+	}
 	return
 }
 
@@ -102,9 +208,83 @@ func (api *API) ItemsGetByApplicationId(id string) (res Items, err error) {
 	return api.ItemsGet(Params{"applicationids": id})
 }
 
+// Gets item by host Id and key only if there is exactly 1 matching item.
+func (api *API) ItemGetByHostIdAndKey(hostId, key string) (res *Item, err error) {
+	items, err := api.ItemsGet(Params{"hostids": hostId, "filter": map[string]string{"key_": key}})
+	if err != nil {
+		return
+	}
+
+	if len(items) == 1 {
+		res = &items[0]
+	} else {
+		e := ExpectedOneResult(len(items))
+		err = &e
+	}
+	return
+}
+
+// Gets item by host Id and key, both taken from item.HostId and item.Key, creating it if it doesn't exist.
+// If it exists and any of its configuration fields differ from given ones, item is updated.
+// Applications are replaced by item.ApplicationIds on update if set.
+func (api *API) ItemEnsure(item Item) (res *Item, err error) {
+	res, err = api.ItemGetByHostIdAndKey(item.HostId, item.Key)
+	if err == nil {
+		if !item.differs(res) {
+			return
+		}
+
+		item.ItemId = res.ItemId
+		err = api.ItemsUpdate(Items{item})
+		if err != nil {
+			return nil, err
+		}
+		return api.ItemGetByHostIdAndKey(item.HostId, item.Key)
+	}
+	if !IsNotFound(err) {
+		return
+	}
+
+	items := Items{item}
+	err = api.ItemsCreate(items)
+	if err != nil {
+		return nil, err
+	}
+	res = &items[0]
+	return
+}
+
+// Reports whether configuration fields of desired item differ from existing one.
+// Empty Delay, History, Trends, InterfaceId and ValueMapId and nil Preprocessing and Tags mean "don't care",
+// as they are omitted on create. Time units are compared by value, so "1m" equals "60".
+// DataType and Delta are compared only if existing item has no preprocessing steps they could be converted to.
+func (item *Item) differs(existing *Item) bool {
+	if len(existing.Preprocessing) == 0 && (item.DataType != existing.DataType || item.Delta != existing.Delta) {
+		return true
+	}
+	if item.Preprocessing != nil && (len(item.Preprocessing) != len(existing.Preprocessing) ||
+		len(item.Preprocessing) > 0 && !reflect.DeepEqual(item.Preprocessing, existing.Preprocessing)) {
+		return true
+	}
+	if item.Tags != nil && !item.Tags.equal(existing.Tags) {
+		return true
+	}
+	return item.Name != existing.Name || item.Type != existing.Type || item.ValueType != existing.ValueType ||
+		item.Description != existing.Description ||
+		(item.Delay != "" && !timeUnitsEqual(item.Delay, existing.Delay)) ||
+		(item.InterfaceId != "" && item.InterfaceId != existing.InterfaceId) ||
+		(item.History != "" && !timeUnitsEqual(item.History, existing.History)) ||
+		(item.Trends != "" && !timeUnitsEqual(item.Trends, existing.Trends)) ||
+		(item.ValueMapId != "" && item.ValueMapId != existing.ValueMapId)
+}
+
 // Wrapper for item.create: https://www.zabbix.com/documentation/2.0/manual/appendix/api/item/create
 func (api *API) ItemsCreate(items Items) (err error) {
-	response, err := api.CallWithError("item.create", items)
+	payload, err := api.itemsPayload(items)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("item.create", payload)
 	if err != nil {
 		return
 	}
@@ -117,6 +297,16 @@ func (api *API) ItemsCreate(items Items) (err error) {
 	return
 }
 
+// Wrapper for item.update: https://www.zabbix.com/documentation/2.0/manual/appendix/api/item/update
+func (api *API) ItemsUpdate(items Items) (err error) {
+	payload, err := api.itemsPayload(items)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("item.update", payload)
+	return
+}
+
 // Wrapper for item.delete: https://www.zabbix.com/documentation/2.0/manual/appendix/api/item/delete
 // Cleans ItemId in all items elements if call succeed.
 func (api *API) ItemsDelete(items Items) (err error) {
@@ -154,3 +344,74 @@ func (api *API) ItemsDeleteByIds(ids []string) (err error) {
 	}
 	return
 }
+
+var timeUnits = map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 7 * 86400}
+
+// Parses time unit like "30", "1m" or "90d" to seconds.
+func parseTimeUnit(s string) (seconds int, ok bool) {
+	mul := 1
+	if l := len(s); l > 0 {
+		if m, present := timeUnits[s[l-1]]; present {
+			mul = m
+			s = s[:l-1]
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return
+	}
+	return n * mul, true
+}
+
+// Reports whether time units are equal: by value if both are simple time units, as strings otherwise.
+func timeUnitsEqual(a, b string) bool {
+	x, okX := parseTimeUnit(a)
+	y, okY := parseTimeUnit(b)
+	if okX && okY {
+		return x == y
+	}
+	return a == b
+}
+
+// Replaces numbers of days in given fields of item map returned before Zabbix 3.4 with time units like "90d".
+func legacyDays(m map[string]interface{}, fields ...string) {
+	for _, f := range fields {
+		if s, ok := m[f].(string); ok && s != "0" {
+			if _, err := strconv.Atoi(s); err == nil {
+				m[f] = s + "d"
+			}
+		}
+	}
+}
+
+// Replaces time units in item payload with seconds (delay) and days (history, trends) used before Zabbix 3.4.
+// Empty values are skipped.
+func legacyTimeUnits(m map[string]interface{}, values map[string]string, v ServerVersion) (err error) {
+	for f, s := range values {
+		if s == "" {
+			continue
+		}
+		seconds, ok := parseTimeUnit(s)
+		if ok && f != "delay" {
+			ok = seconds%86400 == 0
+			seconds /= 86400
+		}
+		if !ok {
+			return &ValidationError{f, fmt.Sprintf("%q can't be represented before Zabbix 3.4 (server %s)", s, v)}
+		}
+		m[f] = seconds
+	}
+	return
+}
+
+// Replaces time units in given fields of object map with integers in given units, so they fit into struct fields.
+func normalizeTimeUnits(m map[string]interface{}, fields map[string]int) {
+	for f, unit := range fields {
+		s, ok := m[f].(string)
+		if !ok {
+			continue
+		}
+		seconds, _ := parseTimeUnit(s)
+		m[f] = strconv.Itoa(seconds / unit)
+	}
+}
@@ -0,0 +1,300 @@
+package zabbix
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parameter of item key: string or array of parameters.
+// Quoted reports whether string parameter was quoted in parsed key; it doesn't change its meaning.
+type ItemKeyParam struct {
+	Value  string
+	Quoted bool
+	Array  ItemKeyParams // non-nil for arrays
+}
+
+type ItemKeyParams []ItemKeyParam
+
+// Item key parsed to name and parameters:
+// https://www.zabbix.com/documentation/current/manual/config/items/item/key
+//
+// Params is nil for keys without brackets; "key[]" has one empty parameter.
+type ItemKey struct {
+	Name   string
+	Params ItemKeyParams
+}
+
+// ItemKeyError is returned by ParseItemKey for malformed keys and by ItemKey.Format for keys which can't be
+// represented; Pos is -1 then. It matches ErrInvalidParams.
+type ItemKeyError struct {
+	Key    string
+	Pos    int
+	Reason string
+}
+
+func (e *ItemKeyError) Error() string {
+	if e.Pos < 0 {
+		return fmt.Sprintf("invalid item key %q: %s", e.Key, e.Reason)
+	}
+	return fmt.Sprintf("invalid item key %q at position %d: %s", e.Key, e.Pos, e.Reason)
+}
+
+func (e *ItemKeyError) Is(target error) bool {
+	return target == ErrInvalidParams
+}
+
+// Returns string parameter. Arrays are formatted as in key.
+func (p ItemKeyParam) String() string {
+	if p.Array != nil {
+		return "[" + p.Array.String() + "]"
+	}
+	return p.Value
+}
+
+// Formats parameters canonically: strings are quoted only if needed.
+func (params ItemKeyParams) String() string {
+	s := make([]string, len(params))
+	for i, p := range params {
+		switch {
+		case p.Array != nil:
+			s[i] = "[" + p.Array.String() + "]"
+		case needsQuoting(p.Value):
+			s[i] = `"` + strings.Replace(p.Value, `"`, `\"`, -1) + `"`
+		default:
+			s[i] = p.Value
+		}
+	}
+	return strings.Join(s, ",")
+}
+
+// Formats key canonically: strings are quoted only if needed, spaces between parameters are removed.
+// Parameters which need quoting and end with backslash can't be represented; use Format to detect them.
+func (key *ItemKey) String() string {
+	if key.Params == nil {
+		return key.Name
+	}
+	return key.Name + "[" + key.Params.String() + "]"
+}
+
+// Formats key as String does. Returns *ItemKeyError if it can't be parsed back: Zabbix doesn't allow
+// backslash at the end of quoted parameter, so parameters which need quoting can't end with it.
+func (key *ItemKey) Format() (s string, err error) {
+	s = key.String()
+	if p := unquotableParam(key.Params); p != nil {
+		err = &ItemKeyError{Key: s, Pos: -1, Reason: fmt.Sprintf("parameter %q needs quoting and can't end with backslash", p.Value)}
+		s = ""
+	}
+	return
+}
+
+// Returns the first parameter which needs quoting and ends with backslash, or nil.
+func unquotableParam(params ItemKeyParams) *ItemKeyParam {
+	for i, p := range params {
+		if p.Array != nil {
+			if res := unquotableParam(p.Array); res != nil {
+				return res
+			}
+			continue
+		}
+		if needsQuoting(p.Value) && strings.HasSuffix(p.Value, `\`) {
+			return &params[i]
+		}
+	}
+	return nil
+}
+
+func needsQuoting(s string) bool {
+	if s == "" {
+		return false
+	}
+	switch s[0] {
+	case '"', '[', ' ':
+		return true
+	}
+
+	// commas and brackets inside macros don't need quoting
+	p := itemKeyParser{s: s}
+	for p.pos < len(s) {
+		c := s[p.pos]
+		if c == ',' || c == ']' {
+			return true
+		}
+		if c == '{' && p.skipMacro() {
+			continue
+		}
+		p.pos++
+	}
+	return false
+}
+
+func isItemKeyNameChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == '-' || c == '.'
+}
+
+// Parses item key. Array parameters can't be nested. User macros (including ones with quoted context), LLD macros and LLD macro functions
+// in unquoted parameters are kept intact, so commas and brackets inside them don't split parameters.
+func ParseItemKey(s string) (key *ItemKey, err error) {
+	p := itemKeyParser{s: s}
+	for p.pos < len(s) && isItemKeyNameChar(s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == 0 {
+		return nil, p.error("expected key name")
+	}
+	key = &ItemKey{Name: s[:p.pos]}
+	if p.pos == len(s) {
+		return
+	}
+	if s[p.pos] != '[' {
+		return nil, p.error(fmt.Sprintf("unexpected character %q", s[p.pos]))
+	}
+
+	key.Params, err = p.params()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(s) {
+		return nil, p.error("unexpected characters after closing bracket")
+	}
+	return
+}
+
+type itemKeyParser struct {
+	s     string
+	pos   int
+	depth int // 1 for key parameters, 2 for array parameters
+}
+
+func (p *itemKeyParser) error(reason string) error {
+	return &ItemKeyError{Key: p.s, Pos: p.pos, Reason: reason}
+}
+
+func (p *itemKeyParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// Parses parameters in brackets starting at current position.
+func (p *itemKeyParser) params() (params ItemKeyParams, err error) {
+	p.pos++ // skip '['
+	p.depth++
+	defer func() { p.depth-- }()
+	params = ItemKeyParams{}
+	for {
+		var param ItemKeyParam
+		param, err = p.param()
+		if err != nil {
+			return
+		}
+		params = append(params, param)
+
+		p.skipSpaces()
+		if p.pos == len(p.s) {
+			err = p.error("unterminated parameters, expected ']'")
+			return
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return
+		default:
+			err = p.error(fmt.Sprintf("unexpected character %q, expected ',' or ']'", p.s[p.pos]))
+			return
+		}
+	}
+}
+
+func (p *itemKeyParser) param() (param ItemKeyParam, err error) {
+	p.skipSpaces()
+	if p.pos == len(p.s) {
+		return
+	}
+
+	switch p.s[p.pos] {
+	case '[':
+		if p.depth > 1 {
+			err = p.error("nested arrays are not allowed")
+			return
+		}
+		param.Array, err = p.params()
+		return
+
+	case '"':
+		param.Quoted = true
+		start := p.pos
+		p.pos++
+		var b strings.Builder
+		for p.pos < len(p.s) {
+			c := p.s[p.pos]
+			switch {
+			case c == '\\' && p.pos+1 < len(p.s) && p.s[p.pos+1] == '"':
+				b.WriteByte('"')
+				p.pos += 2
+			case c == '"':
+				p.pos++
+				param.Value = b.String()
+				return
+			default:
+				b.WriteByte(c)
+				p.pos++
+			}
+		}
+		p.pos = start
+		err = p.error("unterminated quoted parameter")
+		return
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == ',' || c == ']' {
+			break
+		}
+		if c == '{' && p.skipMacro() {
+			continue
+		}
+		p.pos++
+	}
+	param.Value = p.s[start:p.pos]
+	return
+}
+
+// Skips user macro, LLD macro or LLD macro function at current position and returns true.
+// Returns false and doesn't move if there is no complete macro.
+func (p *itemKeyParser) skipMacro() bool {
+	rest := p.s[p.pos:]
+	if !strings.HasPrefix(rest, "{$") && !strings.HasPrefix(rest, "{#") && !strings.HasPrefix(rest, "{{#") {
+		return false
+	}
+
+	depth := 0
+	quoted := false
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(rest):
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				p.pos += i + 1
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Checks that item key is well-formed. Returns *ItemKeyError otherwise.
+func ValidateItemKey(key string) (err error) {
+	_, err = ParseItemKey(key)
+	return
+}
@@ -0,0 +1,99 @@
+package zabbix_test
+
+import (
+	. "."
+	"reflect"
+	"testing"
+)
+
+func TestParseItemKey(t *testing.T) {
+	for s, expected := range map[string]*ItemKey{
+		"agent.ping": {Name: "agent.ping"},
+		"key[]":      {Name: "key", Params: ItemKeyParams{{}}},
+		`vfs.fs.size["/",pfree]`: {Name: "vfs.fs.size", Params: ItemKeyParams{
+			{Value: "/", Quoted: true}, {Value: "pfree"},
+		}},
+		`net.tcp.service[ "a,b" , ,x y]`: {Name: "net.tcp.service", Params: ItemKeyParams{
+			{Value: "a,b", Quoted: true}, {}, {Value: "x y"},
+		}},
+		`key["say \"hi\"",[a,"b]"],]`: {Name: "key", Params: ItemKeyParams{
+			{Value: `say "hi"`, Quoted: true}, {Array: ItemKeyParams{{Value: "a"}, {Value: "b]", Quoted: true}}}, {},
+		}},
+		`vfs.fs.size[{#FSNAME},{$MODE:"a,b]"}]`: {Name: "vfs.fs.size", Params: ItemKeyParams{
+			{Value: "{#FSNAME}"}, {Value: `{$MODE:"a,b]"}`},
+		}},
+		`key[{{#IFNAME}.regsub("(.*),(.*)", \1)},x]`: {Name: "key", Params: ItemKeyParams{
+			{Value: `{{#IFNAME}.regsub("(.*),(.*)", \1)}`}, {Value: "x"},
+		}},
+	} {
+		key, err := ParseItemKey(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(key, expected) {
+			t.Errorf("%s:\nexpected %#v\ngot      %#v", s, expected, key)
+		}
+	}
+
+	for _, s := range []string{"", "[a]", "key[a", `key["a]`, "key[a]b", `key["a"b]`, "key space", "key[[a]", "key[[[a]]]", "key[a,[b,[c]]]", "key[[a],[[b]]]"} {
+		_, err := ParseItemKey(s)
+		if !IsInvalidParams(err) {
+			t.Errorf("%q: expected invalid params error, got %v", s, err)
+		}
+	}
+}
+
+func TestItemKeyString(t *testing.T) {
+	for s, expected := range map[string]string{
+		"agent.ping":                  "agent.ping",
+		"key[]":                       "key[]",
+		`vfs.fs.size["/", "pfree"]`:   "vfs.fs.size[/,pfree]",
+		`key[ "a,b" ,[x, "y"], " z"]`: `key["a,b",[x,y]," z"]`,
+		`key["\"q\"",{$M:"a,b"}]`:     `key["\"q\"",{$M:"a,b"}]`,
+		`web.page.get[localhost,,80]`: "web.page.get[localhost,,80]",
+		`key["[a]"]`:                  `key["[a]"]`,
+	} {
+		key, err := ParseItemKey(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := key.String(); actual != expected {
+			t.Errorf("%s: expected %s, got %s", s, expected, actual)
+		}
+		key2, err := ParseItemKey(key.String())
+		if err != nil || key2.String() != expected {
+			t.Errorf("%s: not stable: %v %v", s, key2, err)
+		}
+		if f, err := key.Format(); err != nil || f != expected {
+			t.Errorf("%s: expected %s, got %s, %v", s, expected, f, err)
+		}
+	}
+
+	// backslash at the end is allowed only in unquoted parameters
+	key := &ItemKey{Name: "key", Params: ItemKeyParams{{Value: `C:\`}, {Array: ItemKeyParams{{Value: `a\`}}}}}
+	s, err := key.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key2, err := ParseItemKey(s); err != nil || !reflect.DeepEqual(key2.Params, key.Params) {
+		t.Errorf("%s: doesn't round-trip: %#v, %v", s, key2, err)
+	}
+	for _, params := range []ItemKeyParams{{{Value: `a,b\`}}, {{Value: `x`}, {Array: ItemKeyParams{{Value: ` a\`}}}}} {
+		key = &ItemKey{Name: "key", Params: params}
+		if _, err = ParseItemKey(key.String()); err == nil {
+			t.Errorf("%s: expected unparseable key", key)
+		}
+		if s, err = key.Format(); !IsInvalidParams(err) || s != "" {
+			t.Errorf("%s: expected invalid params error, got %q, %v", key, s, err)
+		}
+	}
+}
+
+func TestItemsCreateValidatesKey(t *testing.T) {
+	api := NewAPI("http://127.0.0.1:1/api_jsonrpc.php")
+	err := api.ItemsCreate(Items{{HostId: "1", Key: `vfs.fs.size["/,pfree]`}})
+	if _, ok := err.(*ItemKeyError); !ok {
+		t.Errorf("Expected item key error, got %v", err)
+	}
+}
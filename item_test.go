@@ -50,3 +50,33 @@ func TestItems(t *testing.T) {
 	item := CreateItem(app, t)
 	DeleteItem(item, t)
 }
+
+func TestItemEnsure(t *testing.T) {
+	api := getAPI(t)
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	desired := Item{HostId: host.HostId, Key: "key.lala.laa", Name: "name for key", Type: ZabbixTrapper}
+	item, err := api.ItemEnsure(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ItemId == "" {
+		t.Errorf("Id is empty: %#v", item)
+	}
+
+	desired.Name = "new name for key"
+	item2, err := api.ItemEnsure(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item2.ItemId != item.ItemId || item2.Name != desired.Name {
+		t.Errorf("Item is not updated:\n%#v\n%#v", item, item2)
+	}
+
+	DeleteItem(item, t)
+}
@@ -0,0 +1,98 @@
+package zabbix
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Client-side limits for one class of API methods, see API.SetLimits.
+type Limit struct {
+	Rate        float64 // requests per second (token bucket refill rate), unlimited if zero
+	Burst       int     // token bucket size, 1 if zero
+	MaxInFlight int     // maximum number of concurrent requests, unlimited if zero
+}
+
+type limiter struct {
+	limit    Limit
+	inFlight chan struct{} // semaphore, nil if unlimited
+
+	m      sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(limit Limit) *limiter {
+	if limit.Rate <= 0 && limit.MaxInFlight <= 0 {
+		return nil
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	l := &limiter{limit: limit, tokens: float64(limit.Burst), last: time.Now()}
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// Waits for free request slot and token. Returns function which should be called when request is done,
+// or context error.
+func (l *limiter) wait(ctx context.Context) (release func(), err error) {
+	release = func() {}
+	if l == nil {
+		return
+	}
+
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+			release = func() { <-l.inFlight }
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+	}
+
+	if l.limit.Rate > 0 {
+		if d := l.reserve(); d > 0 {
+			t := time.NewTimer(d)
+			defer t.Stop()
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				l.cancel()
+				release()
+				release = func() {}
+				err = ctx.Err()
+			}
+		}
+	}
+	return
+}
+
+// Takes token and returns how long to wait for it.
+func (l *limiter) reserve() time.Duration {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.limit.Rate
+	if max := float64(l.limit.Burst); l.tokens > max {
+		l.tokens = max
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.limit.Rate * float64(time.Second))
+}
+
+// Returns token taken by reserve.
+func (l *limiter) cancel() {
+	l.m.Lock()
+	l.tokens++
+	l.m.Unlock()
+}
@@ -0,0 +1,76 @@
+package zabbix_test
+
+import (
+	. "."
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimits(t *testing.T) {
+	var inFlight, maxInFlight int32
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	api.SetLimits(Limit{MaxInFlight: 2}, Limit{Rate: 10})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := api.HostGroupsGet(Params{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if max := atomic.LoadInt32(&maxInFlight); max != 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", max)
+	}
+
+	// the first write uses burst token, the second one waits 100ms
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := api.CallWithError("hostgroup.delete", []string{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d < 80*time.Millisecond {
+		t.Errorf("Expected writes to be rate limited, took %s", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := api.CallWithErrorContext(ctx, "hostgroup.delete", []string{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	// cancelled wait returns token
+	time.Sleep(100 * time.Millisecond)
+	start = time.Now()
+	if _, err = api.CallWithError("hostgroup.delete", []string{}); err != nil {
+		t.Fatal(err)
+	}
+	// upper bound is skipped in short mode, it is flaky on loaded machines
+	if d := time.Since(start); d > 50*time.Millisecond && !testing.Short() {
+		t.Errorf("Expected write not to wait, took %s", d)
+	}
+}
@@ -0,0 +1,233 @@
+package zabbix
+
+type (
+	MediaTypeType   int
+	MediaTypeStatus int
+	MessageFormat   int
+)
+
+const (
+	MediaTypeEmail     MediaTypeType = 0
+	MediaTypeScript    MediaTypeType = 1
+	MediaTypeSMS       MediaTypeType = 2
+	MediaTypeJabber    MediaTypeType = 3
+	MediaTypeWebhook   MediaTypeType = 4
+	MediaTypeEzTexting MediaTypeType = 100
+
+	MediaTypeEnabled  MediaTypeStatus = 0
+	MediaTypeDisabled MediaTypeStatus = 1
+
+	MessageFormatPlainText MessageFormat = 0
+	MessageFormatHTML      MessageFormat = 1
+)
+
+// Webhook parameter.
+type MediaTypeParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type MediaTypeParameters []MediaTypeParameter
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/object#message-template
+type MessageTemplate struct {
+	EventSource EventSource `json:"eventsource"`
+	Recovery    int         `json:"recovery"` // 0 - problem, 1 - recovery, 2 - update
+	Subject     string      `json:"subject"`
+	Message     string      `json:"message"`
+}
+
+type MessageTemplates []MessageTemplate
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/object
+//
+// Name is sent as description before Zabbix 4.4; Description, webhooks and Parameters are supported since 4.4,
+// MessageTemplates since 5.0. AttemptInterval and Timeout are time units like "10s".
+type MediaType struct {
+	MediaTypeId        string          `json:"mediatypeid,omitempty"`
+	Name               string          `json:"name"`
+	Type               MediaTypeType   `json:"type"`
+	Status             MediaTypeStatus `json:"status"`
+	Description        string          `json:"description,omitempty"`
+	SMTPServer         string          `json:"smtp_server,omitempty"`
+	SMTPHelo           string          `json:"smtp_helo,omitempty"`
+	SMTPEmail          string          `json:"smtp_email,omitempty"`
+	SMTPPort           int             `json:"smtp_port,omitempty"`
+	SMTPSecurity       int             `json:"smtp_security,omitempty"`
+	SMTPVerifyPeer     int             `json:"smtp_verify_peer,omitempty"`
+	SMTPVerifyHost     int             `json:"smtp_verify_host,omitempty"`
+	SMTPAuthentication int             `json:"smtp_authentication,omitempty"`
+	Username           string          `json:"username,omitempty"`
+	Password           string          `json:"passwd,omitempty"`
+	ExecPath           string          `json:"exec_path,omitempty"`
+	ExecParams         string          `json:"exec_params,omitempty"`
+	GsmModem           string          `json:"gsm_modem,omitempty"`
+	MaxSessions        int             `json:"maxsessions,omitempty"`
+	MaxAttempts        int             `json:"maxattempts,omitempty"`
+	AttemptInterval    string          `json:"attempt_interval,omitempty"`
+	MessageFormat      MessageFormat   `json:"content_type,omitempty"`
+	Script             string          `json:"script,omitempty"`
+	Timeout            string          `json:"timeout,omitempty"`
+	ProcessTags        int             `json:"process_tags,omitempty"`
+	ShowEventMenu      int             `json:"show_event_menu,omitempty"`
+	EventMenuURL       string          `json:"event_menu_url,omitempty"`
+	EventMenuName      string          `json:"event_menu_name,omitempty"`
+
+	Parameters       MediaTypeParameters `json:"parameters,omitempty"`
+	MessageTemplates MessageTemplates    `json:"message_templates,omitempty"`
+}
+
+type MediaTypes []MediaType
+
+// Wrapper for mediatype.get: https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/get
+// Message templates are selected by default if supported.
+func (api *API) MediaTypesGet(params Params) (res MediaTypes, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectMessageTemplates"]; !present && v.AtLeast(5, 0) {
+		params["selectMessageTemplates"] = "extend"
+	}
+	response, err := api.CallWithError("mediatype.get", params)
+	if err != nil {
+		return
+	}
+
+	maps := response.Result.([]interface{})
+	if !v.AtLeast(4, 4) {
+		for _, m := range maps {
+			m := m.(map[string]interface{})
+			m["name"] = m["description"]
+			delete(m, "description")
+		}
+	}
+	mapsToStructs(maps, &res)
+	return
+}
+
+// Gets media type by Id only if there is exactly 1 matching media type.
+func (api *API) MediaTypeGetById(id string) (res *MediaType, err error) {
+	mediaTypes, err := api.MediaTypesGet(Params{"mediatypeids": id})
+	if err != nil {
+		return
+	}
+
+	if len(mediaTypes) == 1 {
+		res = &mediaTypes[0]
+	} else {
+		e := ExpectedOneResult(len(mediaTypes))
+		err = &e
+	}
+	return
+}
+
+// Converts media types to payload for mediatype.create and mediatype.update according to server version.
+func (api *API) mediaTypesPayload(mediaTypes MediaTypes) (res []map[string]interface{}, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	res, err = toMaps(mediaTypes)
+	if err != nil {
+		return
+	}
+
+	for i, mt := range mediaTypes {
+		m := res[i]
+		if !v.AtLeast(4, 4) {
+			if mt.Type == MediaTypeWebhook || mt.Description != "" || len(mt.Parameters) > 0 {
+				err = &UnsupportedError{"MediaType webhooks, Description and Parameters", v}
+				return
+			}
+			m["description"] = m["name"]
+			delete(m, "name")
+		}
+		if !v.AtLeast(5, 0) && len(mt.MessageTemplates) > 0 {
+			err = &UnsupportedError{"MediaType MessageTemplates", v}
+			return
+		}
+	}
+	return
+}
+
+// Wrapper for mediatype.create: https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/create
+func (api *API) MediaTypesCreate(mediaTypes MediaTypes) (err error) {
+	payload, err := api.mediaTypesPayload(mediaTypes)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("mediatype.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	mediatypeids := result["mediatypeids"].([]interface{})
+	for i, id := range mediatypeids {
+		mediaTypes[i].MediaTypeId = id.(string)
+	}
+	return
+}
+
+// Wrapper for mediatype.update: https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/update
+func (api *API) MediaTypesUpdate(mediaTypes MediaTypes) (err error) {
+	payload, err := api.mediaTypesPayload(mediaTypes)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("mediatype.update", payload)
+	return
+}
+
+// Wrapper for mediatype.delete: https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/delete
+// Cleans MediaTypeId in all mediaTypes elements if call succeed.
+func (api *API) MediaTypesDelete(mediaTypes MediaTypes) (err error) {
+	ids := make([]string, len(mediaTypes))
+	for i, mt := range mediaTypes {
+		ids[i] = mt.MediaTypeId
+	}
+
+	err = api.MediaTypesDeleteByIds(ids)
+	if err == nil {
+		for i := range mediaTypes {
+			mediaTypes[i].MediaTypeId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for mediatype.delete: https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/delete
+func (api *API) MediaTypesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("mediatype.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	mediatypeids := result["mediatypeids"].([]interface{})
+	if len(ids) != len(mediatypeids) {
+		err = &ExpectedMore{len(ids), len(mediatypeids)}
+	}
+	return
+}
+
+// Wrapper for mediatype.test, which sends test message using media type. params depend on media type:
+// "sendto", "subject" and "message" for e-mail, script and SMS, "parameters" for webhooks.
+// The method is not provided by all server versions; API error is returned then.
+func (api *API) MediaTypeTest(mediaTypeId string, params Params) (res interface{}, err error) {
+	p := Params{"mediatypeid": mediaTypeId}
+	for k, v := range params {
+		p[k] = v
+	}
+	response, err := api.CallWithError("mediatype.test", p)
+	if err != nil {
+		return
+	}
+
+	res = response.Result
+	return
+}
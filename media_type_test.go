@@ -0,0 +1,67 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"testing"
+)
+
+func TestMediaTypes(t *testing.T) {
+	version := "4.2.0"
+	var payload []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "mediatype.create":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			return map[string]interface{}{"mediatypeids": []string{"4"}}, nil
+		case "mediatype.get":
+			if version == "4.2.0" {
+				return []map[string]string{{"mediatypeid": "4", "description": "Email", "type": "0", "smtp_port": "25"}}, nil
+			}
+			return []map[string]interface{}{{"mediatypeid": "4", "name": "Slack", "type": "4", "description": "",
+				"parameters":        []map[string]string{{"name": "channel", "value": "#ops"}},
+				"message_templates": []map[string]string{{"eventsource": "0", "recovery": "1", "subject": "OK", "message": "{EVENT.NAME}"}},
+			}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	mediaTypes := MediaTypes{{Name: "Email", Type: MediaTypeEmail, SMTPServer: "localhost", SMTPPort: 25}}
+	if err := api.MediaTypesCreate(mediaTypes); err != nil {
+		t.Fatal(err)
+	}
+	if payload[0]["description"] != "Email" || payload[0]["name"] != nil {
+		t.Errorf("Bad payload: %#v", payload[0])
+	}
+	mt, err := api.MediaTypeGetById("4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mt.Name != "Email" || mt.SMTPPort != 25 || mt.Description != "" {
+		t.Errorf("Bad media type: %#v", mt)
+	}
+
+	webhook := MediaTypes{{Name: "Slack", Type: MediaTypeWebhook, Parameters: MediaTypeParameters{{"channel", "#ops"}}}}
+	if err = api.MediaTypesCreate(webhook); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+
+	version = "6.0.0"
+	api = NewAPI(srv.URL)
+	if err = api.MediaTypesCreate(webhook); err != nil {
+		t.Fatal(err)
+	}
+	mt, err = api.MediaTypeGetById("4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mt.Name != "Slack" || len(mt.Parameters) != 1 || mt.Parameters[0].Value != "#ops" ||
+		len(mt.MessageTemplates) != 1 || mt.MessageTemplates[0].Recovery != 1 {
+		t.Errorf("Bad media type: %#v", mt)
+	}
+}
@@ -0,0 +1,60 @@
+// Package otelzabbix provides OpenTelemetry tracing for Zabbix API calls.
+//
+// It is a separate package, so core package doesn't depend on OpenTelemetry.
+package otelzabbix
+
+import (
+	"context"
+	"github.com/AlekSi/zabbix"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instrumentation name used for default tracer.
+const InstrumentationName = "github.com/AlekSi/zabbix/otelzabbix"
+
+// Attribute keys of spans.
+const (
+	MethodKey      = attribute.Key("zabbix.method")
+	RequestIdKey   = attribute.Key("zabbix.request_id")
+	ResultCountKey = attribute.Key("zabbix.result_count")
+	ErrorCodeKey   = attribute.Key("zabbix.error_code")
+	CachedKey      = attribute.Key("zabbix.cached")
+)
+
+// Returns interceptor which creates client span "zabbix <method>" per API call, with method, request id,
+// result count and API error code attributes. Tracer from global provider is used if tracer is nil.
+// Install it with API.SetInterceptors.
+func Interceptor(tracer trace.Tracer) zabbix.Interceptor {
+	if tracer == nil {
+		tracer = otel.Tracer(InstrumentationName)
+	}
+
+	return func(ctx context.Context, method string, params interface{}, next zabbix.Invoker) (response zabbix.Response, err error) {
+		ctx, span := tracer.Start(ctx, "zabbix "+method,
+			trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(MethodKey.String(method)))
+		defer span.End()
+
+		ctx, info := zabbix.WithCallInfo(ctx)
+		response, err = next(ctx, method, params)
+
+		span.SetAttributes(CachedKey.Bool(info.Cached))
+		if info.Id != 0 {
+			span.SetAttributes(RequestIdKey.Int(int(info.Id)))
+		}
+		if n, ok := response.ResultCount(); ok {
+			span.SetAttributes(ResultCountKey.Int(n))
+		}
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case response.Error != nil:
+			span.SetAttributes(ErrorCodeKey.Int(response.Error.Code))
+			span.SetStatus(codes.Error, response.Error.Error())
+		}
+		return
+	}
+}
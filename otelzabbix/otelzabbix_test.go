@@ -0,0 +1,69 @@
+package otelzabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"github.com/AlekSi/zabbix"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInterceptor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Id     int32  `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": []interface{}{map[string]string{"hostid": "1"}}}
+		if req.Method == "host.delete" {
+			res = map[string]interface{}{"jsonrpc": "2.0", "id": req.Id,
+				"error": map[string]interface{}{"code": -32602, "message": "Invalid params.", "data": "No permissions."}}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	api := zabbix.NewAPI(srv.URL)
+	api.SetInterceptors(Interceptor(provider.Tracer("test")))
+	if _, err := api.CallWithError("host.get", zabbix.Params{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.CallWithError("host.delete", []string{"1"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	for i, expected := range []struct {
+		name       string
+		attributes map[string]interface{}
+		status     codes.Code
+	}{
+		{"zabbix host.get", map[string]interface{}{"zabbix.method": "host.get", "zabbix.request_id": int64(1), "zabbix.result_count": int64(1)}, codes.Unset},
+		{"zabbix host.delete", map[string]interface{}{"zabbix.method": "host.delete", "zabbix.request_id": int64(2), "zabbix.error_code": int64(-32602)}, codes.Error},
+	} {
+		span := spans[i]
+		if span.Name() != expected.name || span.Status().Code != expected.status {
+			t.Errorf("Bad span: %s %v", span.Name(), span.Status())
+		}
+		attributes := make(map[string]interface{})
+		for _, kv := range span.Attributes() {
+			attributes[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		for k, v := range expected.attributes {
+			if attributes[k] != v {
+				t.Errorf("%s: %s: expected %v, got %v", span.Name(), k, v, attributes[k])
+			}
+		}
+	}
+}
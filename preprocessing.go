@@ -0,0 +1,107 @@
+package zabbix
+
+import (
+	"strings"
+)
+
+type (
+	PreprocessingType         int
+	PreprocessingErrorHandler int
+)
+
+const (
+	CustomMultiplier                    PreprocessingType = 1
+	RightTrim                           PreprocessingType = 2
+	LeftTrim                            PreprocessingType = 3
+	Trim                                PreprocessingType = 4
+	RegularExpression                   PreprocessingType = 5
+	BooleanToDecimal                    PreprocessingType = 6
+	OctalToDecimal                      PreprocessingType = 7
+	HexadecimalToDecimal                PreprocessingType = 8
+	SimpleChange                        PreprocessingType = 9
+	ChangePerSecond                     PreprocessingType = 10
+	XMLXPath                            PreprocessingType = 11
+	JSONPath                            PreprocessingType = 12
+	InRange                             PreprocessingType = 13
+	MatchesRegularExpression            PreprocessingType = 14
+	DoesNotMatchRegularExpression       PreprocessingType = 15
+	CheckForErrorInJSON                 PreprocessingType = 16
+	CheckForErrorInXML                  PreprocessingType = 17
+	CheckForErrorUsingRegularExpression PreprocessingType = 18
+	DiscardUnchanged                    PreprocessingType = 19
+	DiscardUnchangedHeartbeat           PreprocessingType = 20
+	JavaScript                          PreprocessingType = 21
+	PrometheusPattern                   PreprocessingType = 22
+	PrometheusToJSON                    PreprocessingType = 23
+	CSVToJSON                           PreprocessingType = 24
+	Replace                             PreprocessingType = 25
+	CheckUnsupported                    PreprocessingType = 26
+	XMLToJSON                           PreprocessingType = 27
+	SNMPWalkValue                       PreprocessingType = 28
+	SNMPWalkToJSON                      PreprocessingType = 29
+	SNMPGetValue                        PreprocessingType = 30
+
+	ErrorHandlerDefault      PreprocessingErrorHandler = 0
+	ErrorHandlerDiscardValue PreprocessingErrorHandler = 1
+	ErrorHandlerSetValue     PreprocessingErrorHandler = 2
+	ErrorHandlerSetError     PreprocessingErrorHandler = 3
+)
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/item/object#item-preprocessing
+// Preprocessing is available since Zabbix 3.4, error handlers since Zabbix 4.0.
+type PreprocessingStep struct {
+	Type               PreprocessingType         `json:"type"`
+	Params             string                    `json:"params"`
+	ErrorHandler       PreprocessingErrorHandler `json:"error_handler"`
+	ErrorHandlerParams string                    `json:"error_handler_params"`
+}
+
+type PreprocessingSteps []PreprocessingStep
+
+// Creates preprocessing step with default error handler. Multiple params are joined with newlines,
+// for example NewPreprocessingStep(RegularExpression, `(\d+)`, `\1`).
+func NewPreprocessingStep(t PreprocessingType, params ...string) PreprocessingStep {
+	return PreprocessingStep{Type: t, Params: strings.Join(params, "\n")}
+}
+
+// Returns params split by newlines.
+func (step *PreprocessingStep) ParamsList() []string {
+	return strings.Split(step.Params, "\n")
+}
+
+// Returns preprocessing steps equivalent to legacy DataType and Delta item fields,
+// which were replaced by preprocessing in Zabbix 3.4.
+func LegacyPreprocessing(dataType DataType, delta DeltaType) (res PreprocessingSteps) {
+	switch dataType {
+	case Octal:
+		res = append(res, NewPreprocessingStep(OctalToDecimal))
+	case Hexadecimal:
+		res = append(res, NewPreprocessingStep(HexadecimalToDecimal))
+	case Boolean:
+		res = append(res, NewPreprocessingStep(BooleanToDecimal))
+	}
+
+	switch delta {
+	case Speed:
+		res = append(res, NewPreprocessingStep(ChangePerSecond))
+	case Delta:
+		res = append(res, NewPreprocessingStep(SimpleChange))
+	}
+	return
+}
+
+// Converts steps to payload according to server version.
+// Error handlers are not supported before Zabbix 4.0.
+func preprocessingPayload(steps PreprocessingSteps, v ServerVersion) (res []map[string]interface{}, err error) {
+	if v.AtLeast(4, 0) {
+		return toMaps(steps)
+	}
+
+	for _, step := range steps {
+		if step.ErrorHandler != ErrorHandlerDefault || step.ErrorHandlerParams != "" {
+			err = &UnsupportedError{"Preprocessing error handlers", v}
+			return
+		}
+	}
+	return toMaps(steps, "error_handler", "error_handler_params")
+}
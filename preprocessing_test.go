@@ -0,0 +1,74 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPreprocessing(t *testing.T) {
+	version := "4.0.0"
+	var created []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "item.create":
+			created = nil
+			json.Unmarshal(params, &created)
+			return map[string]interface{}{"itemids": []string{"1"}}, nil
+		case "item.get":
+			return []map[string]interface{}{{"itemid": "1", "preprocessing": []map[string]string{
+				{"type": "12", "params": "$.value", "error_handler": "2", "error_handler_params": "0"},
+			}}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	step := NewPreprocessingStep(JSONPath, "$.value")
+	step.ErrorHandler = ErrorHandlerSetValue
+	step.ErrorHandlerParams = "0"
+	items := Items{{HostId: "1", Key: "key", Delta: Speed, Preprocessing: PreprocessingSteps{step}}}
+	if err := api.ItemsCreate(items); err != nil {
+		t.Fatal(err)
+	}
+	if _, present := created[0]["delta"]; present {
+		t.Errorf("Unexpected delta in payload: %#v", created[0])
+	}
+	b, _ := json.Marshal(created[0]["preprocessing"])
+	expected := `[{"error_handler":0,"error_handler_params":"","params":"","type":10},` +
+		`{"error_handler":2,"error_handler_params":"0","params":"$.value","type":12}]`
+	if string(b) != expected {
+		t.Errorf("Bad preprocessing payload:\n%s\n%s", b, expected)
+	}
+
+	res, err := api.ItemsGet(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res[0].Preprocessing, PreprocessingSteps{step}) {
+		t.Errorf("Bad preprocessing: %#v", res[0].Preprocessing)
+	}
+
+	version = "3.4.0"
+	api = NewAPI(srv.URL)
+	if err = api.ItemsCreate(items); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+	if err = api.ItemsCreate(Items{{HostId: "1", Key: "key", DataType: Hexadecimal}}); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = json.Marshal(created[0]["preprocessing"])
+	if string(b) != `[{"params":"","type":8}]` {
+		t.Errorf("Bad preprocessing payload: %s", b)
+	}
+
+	version = "2.0.8"
+	api = NewAPI(srv.URL)
+	if err = api.ItemsCreate(items); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+}
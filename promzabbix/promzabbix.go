@@ -0,0 +1,95 @@
+// Package promzabbix provides Prometheus metrics for Zabbix API calls.
+//
+// It is a separate package, so core package doesn't depend on Prometheus client.
+package promzabbix
+
+import (
+	"context"
+	"github.com/AlekSi/zabbix"
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"time"
+)
+
+// Metrics is a prometheus.Collector with metrics of API calls made through its Interceptor:
+//
+//	zabbix_api_requests_total{method,cached}         counter
+//	zabbix_api_request_duration_seconds{method}      histogram, cached responses are not observed
+//	zabbix_api_request_bytes_total{method}           counter
+//	zabbix_api_response_bytes_total{method}          counter
+//	zabbix_api_errors_total{method,code}             counter, code is API error code or "transport"
+type Metrics struct {
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	bytesSent     *prometheus.CounterVec
+	bytesReceived *prometheus.CounterVec
+	errors        *prometheus.CounterVec
+}
+
+// Creates metrics. Register them with prometheus.Registerer and install Interceptor with API.SetInterceptors.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zabbix", Subsystem: "api", Name: "requests_total",
+			Help: "Total number of Zabbix API calls.",
+		}, []string{"method", "cached"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "zabbix", Subsystem: "api", Name: "request_duration_seconds",
+			Help:    "Latency of Zabbix API calls, excluding cached responses.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+		bytesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zabbix", Subsystem: "api", Name: "request_bytes_total",
+			Help: "Total size of Zabbix API request bodies.",
+		}, []string{"method"}),
+		bytesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zabbix", Subsystem: "api", Name: "response_bytes_total",
+			Help: "Total size of Zabbix API response bodies.",
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "zabbix", Subsystem: "api", Name: "errors_total",
+			Help: "Total number of failed Zabbix API calls by API error code.",
+		}, []string{"method", "code"}),
+	}
+}
+
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.requests, m.duration, m.bytesSent, m.bytesReceived, m.errors}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// Returns interceptor which updates metrics.
+func (m *Metrics) Interceptor() zabbix.Interceptor {
+	return func(ctx context.Context, method string, params interface{}, next zabbix.Invoker) (response zabbix.Response, err error) {
+		ctx, info := zabbix.WithCallInfo(ctx)
+		start := time.Now()
+		response, err = next(ctx, method, params)
+
+		m.requests.WithLabelValues(method, strconv.FormatBool(info.Cached)).Inc()
+		if !info.Cached {
+			m.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+			m.bytesSent.WithLabelValues(method).Add(float64(info.BytesSent))
+			m.bytesReceived.WithLabelValues(method).Add(float64(info.BytesReceived))
+		}
+		switch {
+		case err != nil:
+			m.errors.WithLabelValues(method, "transport").Inc()
+		case response.Error != nil:
+			m.errors.WithLabelValues(method, strconv.Itoa(response.Error.Code)).Inc()
+		}
+		return
+	}
+}
@@ -0,0 +1,77 @@
+package promzabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"github.com/AlekSi/zabbix"
+	"github.com/prometheus/client_golang/prometheus"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Id     int32  `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": []interface{}{}}
+		if req.Method == "host.delete" {
+			res = map[string]interface{}{"jsonrpc": "2.0", "id": req.Id,
+				"error": map[string]interface{}{"code": -32602, "message": "Invalid params.", "data": "No permissions."}}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	metrics := NewMetrics()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics)
+
+	api := zabbix.NewAPI(srv.URL)
+	api.SetInterceptors(metrics.Interceptor())
+	for i := 0; i < 2; i++ {
+		if _, err := api.CallWithError("host.get", zabbix.Params{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := api.CallWithError("host.delete", []string{"1"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]float64)
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			key := f.GetName()
+			for _, l := range m.GetLabel() {
+				key += " " + l.GetName() + "=" + l.GetValue()
+			}
+			switch {
+			case m.GetCounter() != nil:
+				values[key] = m.GetCounter().GetValue()
+			case m.GetHistogram() != nil:
+				values[key] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	for key, expected := range map[string]float64{
+		"zabbix_api_requests_total cached=false method=host.get":    2,
+		"zabbix_api_requests_total cached=false method=host.delete": 1,
+		"zabbix_api_request_duration_seconds method=host.get":       2,
+		"zabbix_api_errors_total code=-32602 method=host.delete":    1,
+	} {
+		if values[key] != expected {
+			t.Errorf("%s: expected %v, got %v", key, expected, values[key])
+		}
+	}
+	if values["zabbix_api_request_bytes_total method=host.get"] == 0 || values["zabbix_api_response_bytes_total method=host.get"] == 0 {
+		t.Errorf("Bad bytes metrics: %v", values)
+	}
+}
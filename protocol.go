@@ -0,0 +1,81 @@
+package zabbix
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Zabbix protocol header: https://www.zabbix.com/documentation/current/manual/appendix/protocols/header_datalen
+const (
+	protocolFlagZabbix     = 0x01
+	protocolFlagCompressed = 0x02
+	protocolFlagLarge      = 0x04
+
+	// Maximum size of data accepted by readPacket, same as in Zabbix server.
+	maxPacketSize = 1 << 30
+)
+
+var protocolMagic = []byte("ZBXD")
+
+// Writes data with Zabbix protocol header without compression.
+func writePacket(w io.Writer, data []byte) (err error) {
+	packet := make([]byte, 13, 13+len(data))
+	copy(packet, protocolMagic)
+	packet[4] = protocolFlagZabbix
+	binary.LittleEndian.PutUint32(packet[5:], uint32(len(data)))
+	packet = append(packet, data...)
+	_, err = w.Write(packet)
+	return
+}
+
+// Reads data with Zabbix protocol header, decompressing it if needed.
+func readPacket(r io.Reader) (data []byte, err error) {
+	header := make([]byte, 5)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return
+	}
+	if !bytes.Equal(header[:4], protocolMagic) || header[4]&protocolFlagZabbix == 0 {
+		err = fmt.Errorf("Bad Zabbix protocol header %q.", header)
+		return
+	}
+	flags := header[4]
+
+	var size, reserved uint64
+	if flags&protocolFlagLarge != 0 {
+		lengths := make([]byte, 16)
+		if _, err = io.ReadFull(r, lengths); err != nil {
+			return
+		}
+		size, reserved = binary.LittleEndian.Uint64(lengths), binary.LittleEndian.Uint64(lengths[8:])
+	} else {
+		lengths := make([]byte, 8)
+		if _, err = io.ReadFull(r, lengths); err != nil {
+			return
+		}
+		size, reserved = uint64(binary.LittleEndian.Uint32(lengths)), uint64(binary.LittleEndian.Uint32(lengths[4:]))
+	}
+	if size > maxPacketSize || (flags&protocolFlagCompressed != 0 && reserved > maxPacketSize) {
+		err = fmt.Errorf("Zabbix protocol packet is too large (%d bytes).", size)
+		return
+	}
+
+	data = make([]byte, size)
+	if _, err = io.ReadFull(r, data); err != nil {
+		return
+	}
+	if flags&protocolFlagCompressed == 0 {
+		return
+	}
+
+	// reserved field contains uncompressed size
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	data = make([]byte, reserved)
+	_, err = io.ReadFull(zr, data)
+	return
+}
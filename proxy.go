@@ -0,0 +1,246 @@
+package zabbix
+
+import (
+	"net"
+)
+
+type (
+	ProxyMode int
+)
+
+const (
+	ProxyActive  ProxyMode = 0
+	ProxyPassive ProxyMode = 1
+)
+
+// https://www.zabbix.com/documentation/7.0/manual/api/reference/proxy/object
+//
+// Fields follow Zabbix 7.0 naming and are converted for earlier versions: Name is sent as host, Mode as status,
+// AllowedAddresses as proxy_address (supported since Zabbix 4.0), Address and Port as passive proxy interface.
+// ProxyGroupId, LocalAddress and LocalPort are supported since Zabbix 7.0. TLS fields are described in Host.
+type Proxy struct {
+	ProxyId          string    `json:"proxyid,omitempty"`
+	Name             string    `json:"name"`
+	Mode             ProxyMode `json:"operating_mode"`
+	Description      string    `json:"description"`
+	AllowedAddresses string    `json:"allowed_addresses,omitempty"`
+	Address          string    `json:"address,omitempty"`
+	Port             string    `json:"port,omitempty"`
+	ProxyGroupId     string    `json:"proxy_groupid,omitempty"`
+	LocalAddress     string    `json:"local_address,omitempty"`
+	LocalPort        string    `json:"local_port,omitempty"`
+	TLSConnect       TLSMode   `json:"tls_connect,omitempty"`
+	TLSAccept        TLSMode   `json:"tls_accept,omitempty"`
+	TLSIssuer        string    `json:"tls_issuer,omitempty"`
+	TLSSubject       string    `json:"tls_subject,omitempty"`
+	TLSPSKIdentity   string    `json:"tls_psk_identity,omitempty"`
+	TLSPSK           string    `json:"tls_psk,omitempty"` // write-only
+}
+
+type Proxies []Proxy
+
+// Legacy (before Zabbix 7.0) proxy status values.
+const (
+	legacyProxyActive  = 5
+	legacyProxyPassive = 6
+)
+
+// Wrapper for proxy.get: https://www.zabbix.com/documentation/7.0/manual/api/reference/proxy/get
+func (api *API) ProxiesGet(params Params) (res Proxies, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectInterface"]; !present && !v.AtLeast(7, 0) {
+		params["selectInterface"] = "extend"
+	}
+	response, err := api.CallWithError("proxy.get", params)
+	if err != nil {
+		return
+	}
+
+	maps := response.Result.([]interface{})
+	for _, m := range maps {
+		m := m.(map[string]interface{})
+		if !v.AtLeast(7, 0) {
+			m["name"] = m["host"]
+			m["operating_mode"] = "0"
+			if m["status"] == "6" {
+				m["operating_mode"] = "1"
+			}
+			if addr, present := m["proxy_address"]; present {
+				m["allowed_addresses"] = addr
+			}
+			if iface, ok := m["interface"].(map[string]interface{}); ok {
+				m["address"] = iface["dns"]
+				if iface["useip"] == "1" {
+					m["address"] = iface["ip"]
+				}
+				m["port"] = iface["port"]
+			}
+			for _, f := range []string{"host", "status", "proxy_address", "interface"} {
+				delete(m, f)
+			}
+		}
+		if m["proxy_groupid"] == "0" {
+			delete(m, "proxy_groupid")
+		}
+	}
+	mapsToStructs(maps, &res)
+	return
+}
+
+// Gets proxy by Id only if there is exactly 1 matching proxy.
+func (api *API) ProxyGetById(id string) (res *Proxy, err error) {
+	proxies, err := api.ProxiesGet(Params{"proxyids": id})
+	if err != nil {
+		return
+	}
+
+	if len(proxies) == 1 {
+		res = &proxies[0]
+	} else {
+		e := ExpectedOneResult(len(proxies))
+		err = &e
+	}
+	return
+}
+
+// Gets proxy by name only if there is exactly 1 matching proxy.
+func (api *API) ProxyGetByName(name string) (res *Proxy, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	field := "name"
+	if !v.AtLeast(7, 0) {
+		field = "host"
+	}
+	proxies, err := api.ProxiesGet(Params{"filter": map[string]string{field: name}})
+	if err != nil {
+		return
+	}
+
+	if len(proxies) == 1 {
+		res = &proxies[0]
+	} else {
+		e := ExpectedOneResult(len(proxies))
+		err = &e
+	}
+	return
+}
+
+// Converts proxies to payload for proxy.create and proxy.update according to server version.
+func (api *API) proxiesPayload(proxies Proxies) (res []map[string]interface{}, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	for _, proxy := range proxies {
+		err = checkTLS(v, "Proxy", proxy.TLSConnect, proxy.TLSAccept, proxy.TLSPSKIdentity, proxy.TLSPSK)
+		if err != nil {
+			return
+		}
+	}
+	res, err = toMaps(proxies)
+	if err != nil {
+		return
+	}
+	if v.AtLeast(7, 0) {
+		return
+	}
+
+	for i, proxy := range proxies {
+		m := res[i]
+		if proxy.ProxyGroupId != "" || proxy.LocalAddress != "" || proxy.LocalPort != "" {
+			err = &UnsupportedError{"Proxy ProxyGroupId, LocalAddress and LocalPort", v}
+			return
+		}
+		if proxy.AllowedAddresses != "" && !v.AtLeast(4, 0) {
+			err = &UnsupportedError{"Proxy AllowedAddresses", v}
+			return
+		}
+
+		m["host"] = proxy.Name
+		m["status"] = legacyProxyActive
+		if proxy.AllowedAddresses != "" {
+			m["proxy_address"] = proxy.AllowedAddresses
+		}
+		if proxy.Mode == ProxyPassive {
+			m["status"] = legacyProxyPassive
+			iface := map[string]interface{}{"useip": 0, "ip": "", "dns": proxy.Address, "port": proxy.Port}
+			if net.ParseIP(proxy.Address) != nil {
+				iface["useip"], iface["ip"], iface["dns"] = 1, proxy.Address, ""
+			}
+			m["interface"] = iface
+		}
+		for _, f := range []string{"name", "operating_mode", "allowed_addresses", "address", "port"} {
+			delete(m, f)
+		}
+	}
+	return
+}
+
+// Wrapper for proxy.create: https://www.zabbix.com/documentation/7.0/manual/api/reference/proxy/create
+func (api *API) ProxiesCreate(proxies Proxies) (err error) {
+	payload, err := api.proxiesPayload(proxies)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("proxy.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	proxyids := result["proxyids"].([]interface{})
+	for i, id := range proxyids {
+		proxies[i].ProxyId = id.(string)
+	}
+	return
+}
+
+// Wrapper for proxy.update: https://www.zabbix.com/documentation/7.0/manual/api/reference/proxy/update
+func (api *API) ProxiesUpdate(proxies Proxies) (err error) {
+	payload, err := api.proxiesPayload(proxies)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("proxy.update", payload)
+	return
+}
+
+// Wrapper for proxy.delete: https://www.zabbix.com/documentation/7.0/manual/api/reference/proxy/delete
+// Cleans ProxyId in all proxies elements if call succeed.
+func (api *API) ProxiesDelete(proxies Proxies) (err error) {
+	ids := make([]string, len(proxies))
+	for i, proxy := range proxies {
+		ids[i] = proxy.ProxyId
+	}
+
+	err = api.ProxiesDeleteByIds(ids)
+	if err == nil {
+		for i := range proxies {
+			proxies[i].ProxyId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for proxy.delete: https://www.zabbix.com/documentation/7.0/manual/api/reference/proxy/delete
+func (api *API) ProxiesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("proxy.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	proxyids := result["proxyids"].([]interface{})
+	if len(ids) != len(proxyids) {
+		err = &ExpectedMore{len(ids), len(proxyids)}
+	}
+	return
+}
@@ -0,0 +1,143 @@
+package zabbix
+
+type (
+	ProxyGroupState int
+)
+
+const (
+	ProxyGroupUnknown    ProxyGroupState = 0
+	ProxyGroupOffline    ProxyGroupState = 1
+	ProxyGroupRecovering ProxyGroupState = 2
+	ProxyGroupOnline     ProxyGroupState = 3
+	ProxyGroupDegrading  ProxyGroupState = 4
+)
+
+// https://www.zabbix.com/documentation/7.0/manual/api/reference/proxygroup/object
+// Proxy groups are available since Zabbix 7.0; all wrappers return *UnsupportedError before.
+//
+// FailoverDelay is time unit like "1m", MinOnline may contain user macro.
+type ProxyGroup struct {
+	ProxyGroupId  string          `json:"proxy_groupid,omitempty"`
+	Name          string          `json:"name"`
+	FailoverDelay string          `json:"failover_delay,omitempty"`
+	MinOnline     string          `json:"min_online,omitempty"`
+	Description   string          `json:"description"`
+	State         ProxyGroupState `json:"state"` // read-only
+}
+
+type ProxyGroups []ProxyGroup
+
+func (api *API) checkProxyGroups() (err error) {
+	v, err := api.ServerVersion()
+	if err == nil && !v.AtLeast(7, 0) {
+		err = &UnsupportedError{"Proxy groups", v}
+	}
+	return
+}
+
+// Wrapper for proxygroup.get: https://www.zabbix.com/documentation/7.0/manual/api/reference/proxygroup/get
+func (api *API) ProxyGroupsGet(params Params) (res ProxyGroups, err error) {
+	err = api.checkProxyGroups()
+	if err != nil {
+		return
+	}
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("proxygroup.get", params)
+	if err != nil {
+		return
+	}
+
+	mapsToStructs(response.Result.([]interface{}), &res)
+	return
+}
+
+// Gets proxy group by Id only if there is exactly 1 matching proxy group.
+func (api *API) ProxyGroupGetById(id string) (res *ProxyGroup, err error) {
+	groups, err := api.ProxyGroupsGet(Params{"proxy_groupids": id})
+	if err != nil {
+		return
+	}
+
+	if len(groups) == 1 {
+		res = &groups[0]
+	} else {
+		e := ExpectedOneResult(len(groups))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for proxygroup.create: https://www.zabbix.com/documentation/7.0/manual/api/reference/proxygroup/create
+func (api *API) ProxyGroupsCreate(groups ProxyGroups) (err error) {
+	err = api.checkProxyGroups()
+	if err != nil {
+		return
+	}
+	payload, err := toMaps(groups, "state")
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("proxygroup.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	groupids := result["proxy_groupids"].([]interface{})
+	for i, id := range groupids {
+		groups[i].ProxyGroupId = id.(string)
+	}
+	return
+}
+
+// Wrapper for proxygroup.update: https://www.zabbix.com/documentation/7.0/manual/api/reference/proxygroup/update
+func (api *API) ProxyGroupsUpdate(groups ProxyGroups) (err error) {
+	err = api.checkProxyGroups()
+	if err != nil {
+		return
+	}
+	payload, err := toMaps(groups, "state")
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("proxygroup.update", payload)
+	return
+}
+
+// Wrapper for proxygroup.delete: https://www.zabbix.com/documentation/7.0/manual/api/reference/proxygroup/delete
+// Cleans ProxyGroupId in all groups elements if call succeed.
+func (api *API) ProxyGroupsDelete(groups ProxyGroups) (err error) {
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ProxyGroupId
+	}
+
+	err = api.ProxyGroupsDeleteByIds(ids)
+	if err == nil {
+		for i := range groups {
+			groups[i].ProxyGroupId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for proxygroup.delete: https://www.zabbix.com/documentation/7.0/manual/api/reference/proxygroup/delete
+func (api *API) ProxyGroupsDeleteByIds(ids []string) (err error) {
+	err = api.checkProxyGroups()
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("proxygroup.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	groupids := result["proxy_groupids"].([]interface{})
+	if len(ids) != len(groupids) {
+		err = &ExpectedMore{len(ids), len(groupids)}
+	}
+	return
+}
@@ -0,0 +1,49 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"testing"
+)
+
+func TestProxyGroups(t *testing.T) {
+	version := "7.0.0"
+	var payload []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "proxygroup.create":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			return map[string]interface{}{"proxy_groupids": []string{"2"}}, nil
+		case "proxygroup.get":
+			return []map[string]string{{"proxy_groupid": "2", "name": "dc", "failover_delay": "1m", "min_online": "1", "description": "", "state": "3"}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	groups := ProxyGroups{{Name: "dc", FailoverDelay: "1m", MinOnline: "1", State: ProxyGroupOnline}}
+	if err := api.ProxyGroupsCreate(groups); err != nil {
+		t.Fatal(err)
+	}
+	if _, present := payload[0]["state"]; present || groups[0].ProxyGroupId != "2" {
+		t.Errorf("Bad payload: %#v", payload[0])
+	}
+
+	group, err := api.ProxyGroupGetById("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *group != groups[0] {
+		t.Errorf("Proxy groups are not equal:\n%#v\n%#v", *group, groups[0])
+	}
+
+	version = "6.4.0"
+	api = NewAPI(srv.URL)
+	if _, err = api.ProxyGroupsGet(Params{}); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"testing"
+)
+
+func TestProxies(t *testing.T) {
+	version := "6.0.0"
+	var payload []map[string]interface{}
+	var massupdate map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "proxy.create":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			return map[string]interface{}{"proxyids": []string{"11"}}, nil
+		case "proxy.get":
+			return []map[string]interface{}{{"proxyid": "11", "host": "dc1", "status": "6", "description": "",
+				"interface": map[string]string{"useip": "1", "ip": "10.0.0.1", "dns": "", "port": "10051"}}}, nil
+		case "host.massupdate":
+			massupdate = nil
+			json.Unmarshal(params, &massupdate)
+			return map[string]interface{}{"hostids": []string{"1", "2"}}, nil
+		case "host.get":
+			return []map[string]string{{"hostid": "1", "host": "h1", "proxy_hostid": "11"}, {"hostid": "2", "host": "h2", "proxy_hostid": "0"}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	proxies := Proxies{{Name: "dc1", Mode: ProxyPassive, Address: "10.0.0.1", Port: "10051"}}
+	if err := api.ProxiesCreate(proxies); err != nil {
+		t.Fatal(err)
+	}
+	m := payload[0]
+	iface, _ := m["interface"].(map[string]interface{})
+	if m["host"] != "dc1" || m["status"] != float64(6) || m["name"] != nil || iface["ip"] != "10.0.0.1" || iface["useip"] != float64(1) {
+		t.Errorf("Bad payload: %#v", m)
+	}
+
+	proxy, err := api.ProxyGetById("11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxies[0].ProxyId = "11"
+	if *proxy != proxies[0] {
+		t.Errorf("Proxies are not equal:\n%#v\n%#v", *proxy, proxies[0])
+	}
+
+	hosts, err := api.HostsGet(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hosts[0].ProxyId != "11" || hosts[1].ProxyId != "" {
+		t.Errorf("Bad hosts: %#v", hosts)
+	}
+	if err = api.HostsMoveToProxy(hosts, "12"); err != nil {
+		t.Fatal(err)
+	}
+	if massupdate["proxy_hostid"] != "12" || len(massupdate["hosts"].([]interface{})) != 2 || hosts[1].ProxyId != "12" {
+		t.Errorf("Bad mass update: %#v", massupdate)
+	}
+	if err = api.HostsMoveToProxyGroup(hosts, "1"); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+
+	version = "7.0.0"
+	api = NewAPI(srv.URL)
+	if err = api.ProxiesCreate(proxies); err != nil {
+		t.Fatal(err)
+	}
+	if payload[0]["name"] != "dc1" || payload[0]["operating_mode"] != float64(1) || payload[0]["address"] != "10.0.0.1" {
+		t.Errorf("Bad payload: %#v", payload[0])
+	}
+	if err = api.HostsMoveToProxy(hosts, "12"); err != nil {
+		t.Fatal(err)
+	}
+	if massupdate["proxyid"] != "12" || massupdate["monitored_by"] != float64(MonitoredByProxy) {
+		t.Errorf("Bad mass update: %#v", massupdate)
+	}
+}
@@ -0,0 +1,152 @@
+package zabbix
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/role/object
+// Roles are available since Zabbix 5.2; all wrappers return *UnsupportedError before.
+//
+// Rules are kept as is, see https://www.zabbix.com/documentation/current/manual/api/reference/role/object#role-rules.
+type Role struct {
+	RoleId   string   `json:"roleid,omitempty"`
+	Name     string   `json:"name"`
+	Type     UserType `json:"type"`
+	ReadOnly int      `json:"readonly,omitempty"`
+
+	// Filled by RolesGet
+	Rules Params `json:"rules,omitempty"`
+}
+
+type Roles []Role
+
+func (api *API) checkRoles() (err error) {
+	v, err := api.ServerVersion()
+	if err == nil && !v.AtLeast(5, 2) {
+		err = &UnsupportedError{"Roles", v}
+	}
+	return
+}
+
+// Wrapper for role.get: https://www.zabbix.com/documentation/current/manual/api/reference/role/get
+// Rules are selected by default.
+func (api *API) RolesGet(params Params) (res Roles, err error) {
+	err = api.checkRoles()
+	if err != nil {
+		return
+	}
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectRules"]; !present {
+		params["selectRules"] = "extend"
+	}
+	response, err := api.CallWithError("role.get", params)
+	if err != nil {
+		return
+	}
+
+	mapsToStructs(response.Result.([]interface{}), &res)
+	return
+}
+
+// Gets role by Id only if there is exactly 1 matching role.
+func (api *API) RoleGetById(id string) (res *Role, err error) {
+	roles, err := api.RolesGet(Params{"roleids": id})
+	if err != nil {
+		return
+	}
+
+	if len(roles) == 1 {
+		res = &roles[0]
+	} else {
+		e := ExpectedOneResult(len(roles))
+		err = &e
+	}
+	return
+}
+
+// Gets role by name only if there is exactly 1 matching role.
+func (api *API) RoleGetByName(name string) (res *Role, err error) {
+	roles, err := api.RolesGet(Params{"filter": map[string]string{"name": name}})
+	if err != nil {
+		return
+	}
+
+	if len(roles) == 1 {
+		res = &roles[0]
+	} else {
+		e := ExpectedOneResult(len(roles))
+		err = &e
+	}
+	return
+}
+
+// Wrapper for role.create: https://www.zabbix.com/documentation/current/manual/api/reference/role/create
+func (api *API) RolesCreate(roles Roles) (err error) {
+	err = api.checkRoles()
+	if err != nil {
+		return
+	}
+	payload, err := toMaps(roles, "readonly")
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("role.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	roleids := result["roleids"].([]interface{})
+	for i, id := range roleids {
+		roles[i].RoleId = id.(string)
+	}
+	return
+}
+
+// Wrapper for role.update: https://www.zabbix.com/documentation/current/manual/api/reference/role/update
+func (api *API) RolesUpdate(roles Roles) (err error) {
+	err = api.checkRoles()
+	if err != nil {
+		return
+	}
+	payload, err := toMaps(roles, "readonly")
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("role.update", payload)
+	return
+}
+
+// Wrapper for role.delete: https://www.zabbix.com/documentation/current/manual/api/reference/role/delete
+// Cleans RoleId in all roles elements if call succeed.
+func (api *API) RolesDelete(roles Roles) (err error) {
+	ids := make([]string, len(roles))
+	for i, role := range roles {
+		ids[i] = role.RoleId
+	}
+
+	err = api.RolesDeleteByIds(ids)
+	if err == nil {
+		for i := range roles {
+			roles[i].RoleId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for role.delete: https://www.zabbix.com/documentation/current/manual/api/reference/role/delete
+func (api *API) RolesDeleteByIds(ids []string) (err error) {
+	err = api.checkRoles()
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("role.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	roleids := result["roleids"].([]interface{})
+	if len(ids) != len(roleids) {
+		err = &ExpectedMore{len(ids), len(roleids)}
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"testing"
+)
+
+func TestRoles(t *testing.T) {
+	version := "6.0.0"
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "role.get":
+			return []map[string]interface{}{{"roleid": "4", "name": "Operator", "type": "1", "readonly": "0",
+				"rules": map[string]interface{}{"ui.default_access": "1"}}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	role, err := api.RoleGetByName("Operator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role.RoleId != "4" || role.Type != UserTypeUser || role.Rules["ui.default_access"] != "1" {
+		t.Errorf("Bad role: %#v", role)
+	}
+
+	version = "5.0.0"
+	api = NewAPI(srv.URL)
+	if _, err = api.RolesGet(Params{}); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+}
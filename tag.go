@@ -0,0 +1,138 @@
+package zabbix
+
+type (
+	TagOperator int
+)
+
+const (
+	TagContains    TagOperator = 0
+	TagEquals      TagOperator = 1
+	TagNotContains TagOperator = 2
+	TagNotEquals   TagOperator = 3
+	TagExists      TagOperator = 4
+	TagNotExists   TagOperator = 5
+)
+
+// Tag of host, template, item or trigger. Supported on triggers since Zabbix 3.2,
+// on hosts and templates since 4.2, on items since 5.4.
+type Tag struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+type Tags []Tag
+
+// Condition of tag-based filtering in get methods. Operators other than TagContains and TagEquals
+// are supported since Zabbix 5.4.
+type TagCondition struct {
+	Tag      string      `json:"tag"`
+	Value    string      `json:"value,omitempty"`
+	Operator TagOperator `json:"operator"`
+}
+
+type TagConditions []TagCondition
+
+// Adds tag-based filtering to params of host.get, template.get, item.get or trigger.get and returns params.
+// evalType is EvalAndOr (conditions with the same tag are combined with Or, others with And) or EvalOr.
+func (params Params) FilterByTags(evalType EvalType, conditions TagConditions) Params {
+	params["evaltype"] = evalType
+	params["tags"] = conditions
+	return params
+}
+
+// Reports whether tags contain tag with given name and value.
+func (tags Tags) Has(tag Tag) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether tags contain the same tags as other in any order.
+func (tags Tags) equal(other Tags) bool {
+	if len(tags) != len(other) {
+		return false
+	}
+	for _, t := range tags {
+		if !other.Has(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns tags with added tags (if they are not present yet) and removed tags.
+// Removed tag with empty value removes all tags with that name.
+func (tags Tags) modify(add, remove Tags) (res Tags, changed bool) {
+	res = make(Tags, 0, len(tags)+len(add))
+	for _, t := range tags {
+		removed := false
+		for _, r := range remove {
+			if r.Tag == t.Tag && (r.Value == "" || r.Value == t.Value) {
+				removed = true
+				break
+			}
+		}
+		if removed {
+			changed = true
+			continue
+		}
+		res = append(res, t)
+	}
+	for _, a := range add {
+		if !res.Has(a) {
+			res = append(res, a)
+			changed = true
+		}
+	}
+	return
+}
+
+// Adds and removes tags of objects with given ids by calling <object>.get and <object>.update,
+// as there is no mass update for tags. Objects with unchanged tags are not updated.
+func (api *API) updateTags(object, idField string, ids []string, add, remove Tags) (err error) {
+	params := Params{idField + "s": ids, "output": []string{idField}, "selectTags": "extend"}
+	response, err := api.CallWithError(object+".get", params)
+	if err != nil {
+		return
+	}
+
+	var payload []map[string]interface{}
+	for _, m := range response.Result.([]interface{}) {
+		m := m.(map[string]interface{})
+		var tags Tags
+		values, _ := m["tags"].([]interface{})
+		mapsToStructs(values, &tags)
+		tags, changed := tags.modify(add, remove)
+		if changed {
+			payload = append(payload, map[string]interface{}{idField: m[idField], "tags": tags})
+		}
+	}
+	if len(payload) == 0 {
+		return
+	}
+	_, err = api.CallWithError(object+".update", payload)
+	return
+}
+
+// Adds and removes tags of hosts with given ids. Removed tag with empty value removes all tags with that name.
+func (api *API) HostsUpdateTags(ids []string, add, remove Tags) (err error) {
+	return api.updateTags("host", "hostid", ids, add, remove)
+}
+
+// Adds and removes tags of templates with given ids. Removed tag with empty value removes all tags with that name.
+func (api *API) TemplatesUpdateTags(ids []string, add, remove Tags) (err error) {
+	return api.updateTags("template", "templateid", ids, add, remove)
+}
+
+// Adds and removes tags of items with given ids. Removed tag with empty value removes all tags with that name.
+func (api *API) ItemsUpdateTags(ids []string, add, remove Tags) (err error) {
+	return api.updateTags("item", "itemid", ids, add, remove)
+}
+
+// Adds and removes tags of triggers with given ids. Removed tag with empty value removes all tags with that name.
+func (api *API) TriggersUpdateTags(ids []string, add, remove Tags) (err error) {
+	return api.updateTags("trigger", "triggerid", ids, add, remove)
+}
@@ -0,0 +1,99 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	version := "5.4.0"
+	var getParams map[string]interface{}
+	var payload []map[string]interface{}
+	updates := 0
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "host.get", "trigger.get":
+			getParams = nil
+			json.Unmarshal(params, &getParams)
+			return []map[string]interface{}{
+				{"hostid": "1", "triggerid": "1", "tags": []map[string]string{{"tag": "owner", "value": "db"}, {"tag": "env", "value": "prod"}}},
+				{"hostid": "2", "triggerid": "2", "tags": []map[string]string{{"tag": "owner", "value": "web"}}},
+			}, nil
+		case "host.update", "host.create", "item.create":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			updates++
+			return map[string]interface{}{"hostids": []string{"1"}, "itemids": []string{"1"}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	params := Params{"selectTags": "extend"}.FilterByTags(EvalOr, TagConditions{{Tag: "owner", Value: "db", Operator: TagEquals}, {Tag: "env", Operator: TagExists}})
+	hosts, err := api.HostsGet(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, _ := getParams["tags"].([]interface{})
+	if getParams["evaltype"] != float64(EvalOr) || len(tags) != 2 || tags[1].(map[string]interface{})["operator"] != float64(TagExists) {
+		t.Errorf("Bad params: %#v", getParams)
+	}
+	if !reflect.DeepEqual(hosts[0].Tags, Tags{{"owner", "db"}, {"env", "prod"}}) {
+		t.Errorf("Bad tags: %#v", hosts[0].Tags)
+	}
+
+	triggers, err := api.TriggersGet(Params{"selectTags": "extend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !triggers[1].Tags.Has(Tag{"owner", "web"}) {
+		t.Errorf("Bad tags: %#v", triggers[1].Tags)
+	}
+
+	err = api.HostsUpdateTags([]string{"1", "2"}, Tags{{"env", "prod"}}, Tags{{"owner", ""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{
+		{"hostid": "1", "tags": []interface{}{map[string]interface{}{"tag": "env", "value": "prod"}}},
+		{"hostid": "2", "tags": []interface{}{map[string]interface{}{"tag": "env", "value": "prod"}}},
+	}
+	if !reflect.DeepEqual(payload, expected) {
+		t.Errorf("Bad payload: %#v", payload)
+	}
+
+	updates = 0
+	if err = api.HostsUpdateTags([]string{"1", "2"}, Tags{{"owner", "web"}}, Tags{{"owner", "db"}}); err != nil {
+		t.Fatal(err)
+	}
+	if updates != 1 || len(payload) != 1 || payload[0]["hostid"] != "1" {
+		t.Errorf("Bad payload: %#v", payload)
+	}
+
+	if err = api.ItemsCreate(Items{{HostId: "1", Key: "key", Tags: Tags{{"component", "cpu"}}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, present := payload[0]["tags"]; !present {
+		t.Errorf("Bad payload: %#v", payload[0])
+	}
+
+	version = "5.0.0"
+	api = NewAPI(srv.URL)
+	if err = api.ItemsCreate(Items{{HostId: "1", Key: "key", Tags: Tags{{"component", "cpu"}}}}); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+	if err = api.HostsCreate(Hosts{{Host: "h", Tags: Tags{{"owner", "db"}}}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	version = "4.0.0"
+	api = NewAPI(srv.URL)
+	if err = api.TemplatesCreate(Templates{{Host: "t", Tags: Tags{{"owner", "db"}}}}); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+}
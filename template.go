@@ -0,0 +1,144 @@
+package zabbix
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/template/object
+//
+// GroupIds are template groups since Zabbix 6.2 and host groups before.
+type Template struct {
+	TemplateId  string `json:"templateid,omitempty"`
+	Host        string `json:"host"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Fields below used only when creating templates; they are filled by TemplatesGet with "selectGroups"
+	// and "selectTags" params. Tags are supported since Zabbix 4.2
+	GroupIds HostGroupIds `json:"groups,omitempty"`
+	Tags     Tags         `json:"tags,omitempty"`
+}
+
+type Templates []Template
+
+// Wrapper for template.get: https://www.zabbix.com/documentation/current/manual/api/reference/template/get
+func (api *API) TemplatesGet(params Params) (res Templates, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	response, err := api.CallWithError("template.get", params)
+	if err != nil {
+		return
+	}
+
+	mapsToStructs(response.Result.([]interface{}), &res)
+	return
+}
+
+// Gets template by Id only if there is exactly 1 matching template.
+func (api *API) TemplateGetById(id string) (res *Template, err error) {
+	templates, err := api.TemplatesGet(Params{"templateids": id})
+	if err != nil {
+		return
+	}
+
+	if len(templates) == 1 {
+		res = &templates[0]
+	} else {
+		e := ExpectedOneResult(len(templates))
+		err = &e
+	}
+	return
+}
+
+// Gets template by Host only if there is exactly 1 matching template.
+func (api *API) TemplateGetByHost(host string) (res *Template, err error) {
+	templates, err := api.TemplatesGet(Params{"filter": map[string]string{"host": host}})
+	if err != nil {
+		return
+	}
+
+	if len(templates) == 1 {
+		res = &templates[0]
+	} else {
+		e := ExpectedOneResult(len(templates))
+		err = &e
+	}
+	return
+}
+
+// Converts templates to payload for template.create and template.update according to server version.
+// Version is detected only if templates use fields which depend on it.
+func (api *API) templatesPayload(templates Templates) (res []map[string]interface{}, err error) {
+	for _, template := range templates {
+		if len(template.Tags) > 0 {
+			var v ServerVersion
+			v, err = api.ServerVersion()
+			if err == nil && !v.AtLeast(4, 2) {
+				err = &UnsupportedError{"Template Tags", v}
+			}
+			if err != nil {
+				return
+			}
+			break
+		}
+	}
+	return toMaps(templates)
+}
+
+// Wrapper for template.create: https://www.zabbix.com/documentation/current/manual/api/reference/template/create
+func (api *API) TemplatesCreate(templates Templates) (err error) {
+	payload, err := api.templatesPayload(templates)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("template.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	templateids := result["templateids"].([]interface{})
+	for i, id := range templateids {
+		templates[i].TemplateId = id.(string)
+	}
+	return
+}
+
+// Wrapper for template.update: https://www.zabbix.com/documentation/current/manual/api/reference/template/update
+func (api *API) TemplatesUpdate(templates Templates) (err error) {
+	payload, err := api.templatesPayload(templates)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("template.update", payload)
+	return
+}
+
+// Wrapper for template.delete: https://www.zabbix.com/documentation/current/manual/api/reference/template/delete
+// Cleans TemplateId in all templates elements if call succeed.
+func (api *API) TemplatesDelete(templates Templates) (err error) {
+	ids := make([]string, len(templates))
+	for i, template := range templates {
+		ids[i] = template.TemplateId
+	}
+
+	err = api.TemplatesDeleteByIds(ids)
+	if err == nil {
+		for i := range templates {
+			templates[i].TemplateId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for template.delete: https://www.zabbix.com/documentation/current/manual/api/reference/template/delete
+func (api *API) TemplatesDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("template.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	templateids := result["templateids"].([]interface{})
+	if len(ids) != len(templateids) {
+		err = &ExpectedMore{len(ids), len(templateids)}
+	}
+	return
+}
@@ -0,0 +1,123 @@
+package zabbix
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+type (
+	TLSMode int
+)
+
+// Values of TLSConnect; TLSAccept is a bitmask of them.
+const (
+	TLSNoEncryption TLSMode = 1
+	TLSPSK          TLSMode = 2
+	TLSCertificate  TLSMode = 4
+)
+
+// Limits for pre-shared keys from Zabbix documentation.
+const (
+	MinPSKLength         = 32  // hex digits, 128 bits
+	MaxPSKLength         = 512 // hex digits, 2048 bits
+	MaxPSKIdentityLength = 128
+)
+
+// Validates PSK identity and hex-encoded pre-shared key as Zabbix does.
+// Returns *ValidationError matching ErrInvalidParams.
+func ValidatePSK(identity, psk string) error {
+	if identity == "" {
+		return &ValidationError{"tls_psk_identity", "empty"}
+	}
+	if len(identity) > MaxPSKIdentityLength {
+		return &ValidationError{"tls_psk_identity", fmt.Sprintf("longer than %d characters", MaxPSKIdentityLength)}
+	}
+	if len(psk) < MinPSKLength || len(psk) > MaxPSKLength {
+		return &ValidationError{"tls_psk", fmt.Sprintf("should contain from %d to %d hex digits, got %d",
+			MinPSKLength, MaxPSKLength, len(psk))}
+	}
+	if len(psk)%2 != 0 {
+		return &ValidationError{"tls_psk", "odd number of hex digits"}
+	}
+	if _, err := hex.DecodeString(psk); err != nil {
+		return &ValidationError{"tls_psk", "not a hex string"}
+	}
+	return nil
+}
+
+// Generates random 256-bit pre-shared key as hex string, like "openssl rand -hex 32".
+func GeneratePSK() (psk string, err error) {
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err == nil {
+		psk = hex.EncodeToString(b)
+	}
+	return
+}
+
+// Checks TLS settings of host or proxy before sending them to server with given version.
+// PSK is validated only if set, as it is write-only and not required on update.
+func checkTLS(v ServerVersion, object string, connect, accept TLSMode, identity, psk string) (err error) {
+	if connect == 0 && accept == 0 && identity == "" && psk == "" {
+		return
+	}
+	if !v.AtLeast(3, 0) {
+		return &UnsupportedError{object + " TLS settings", v}
+	}
+	switch connect {
+	case 0, TLSNoEncryption, TLSPSK, TLSCertificate:
+	default:
+		return &ValidationError{"tls_connect", fmt.Sprintf("unexpected value %d", connect)}
+	}
+	if accept < 0 || accept > TLSNoEncryption|TLSPSK|TLSCertificate {
+		return &ValidationError{"tls_accept", fmt.Sprintf("unexpected value %d", accept)}
+	}
+	if psk != "" {
+		return ValidatePSK(identity, psk)
+	}
+	return
+}
+
+// Sets new random pre-shared keys for hosts using host.update and stores them in TLSPSK of hosts elements,
+// so they can be deployed to agents. PSK identities are kept, or set by identity function if it is not nil.
+// TLSConnect and TLSAccept are sent if set. As identities are write-only since Zabbix 5.4, they should be
+// set in hosts or by identity function.
+func (api *API) HostsRotatePSK(hosts Hosts, identity func(host *Host) string) (err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+
+	updated := make(Hosts, len(hosts))
+	payload := make([]map[string]interface{}, len(hosts))
+	for i, host := range hosts {
+		if identity != nil {
+			host.TLSPSKIdentity = identity(&hosts[i])
+		}
+		host.TLSPSK, err = GeneratePSK()
+		if err != nil {
+			return
+		}
+		err = checkTLS(v, "Host", host.TLSConnect, host.TLSAccept, host.TLSPSKIdentity, host.TLSPSK)
+		if err != nil {
+			return
+		}
+
+		m := map[string]interface{}{"hostid": host.HostId, "tls_psk_identity": host.TLSPSKIdentity, "tls_psk": host.TLSPSK}
+		if host.TLSConnect != 0 {
+			m["tls_connect"] = host.TLSConnect
+		}
+		if host.TLSAccept != 0 {
+			m["tls_accept"] = host.TLSAccept
+		}
+		payload[i] = m
+		updated[i] = host
+	}
+
+	_, err = api.CallWithError("host.update", payload)
+	if err == nil {
+		copy(hosts, updated)
+	}
+	return
+}
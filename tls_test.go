@@ -0,0 +1,147 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidatePSK(t *testing.T) {
+	psk, err := GeneratePSK()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(psk) != 64 {
+		t.Errorf("Bad PSK: %q", psk)
+	}
+
+	for _, c := range []struct {
+		identity, psk string
+		valid         bool
+	}{
+		{"PSK 001", psk, true},
+		{"PSK 001", strings.Repeat("0a", 16), true},
+		{"PSK 001", strings.Repeat("0a", 15), false},
+		{"PSK 001", strings.Repeat("0a", 257), false},
+		{"PSK 001", strings.Repeat("0a", 16) + "b", false},
+		{"PSK 001", strings.Repeat("zz", 16), false},
+		{"", psk, false},
+		{strings.Repeat("x", 129), psk, false},
+	} {
+		err := ValidatePSK(c.identity, c.psk)
+		if (err == nil) != c.valid {
+			t.Errorf("%q %q: unexpected result %v", c.identity, c.psk, err)
+		}
+		if err != nil && !IsInvalidParams(err) {
+			t.Errorf("Expected invalid params error, got %v", err)
+		}
+	}
+}
+
+func TestHostsTLS(t *testing.T) {
+	version := "6.0.0"
+	var payload []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "host.create", "host.update":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			ids := make([]string, len(payload))
+			for i := range ids {
+				ids[i] = "1"
+			}
+			return map[string]interface{}{"hostids": ids}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	psk := strings.Repeat("ab", 16)
+	hosts := Hosts{{Host: "h1", TLSConnect: TLSPSK, TLSAccept: TLSPSK | TLSNoEncryption, TLSPSKIdentity: "PSK h1", TLSPSK: psk}}
+	if err := api.HostsCreate(hosts); err != nil {
+		t.Fatal(err)
+	}
+	m := payload[0]
+	if m["tls_connect"] != float64(2) || m["tls_accept"] != float64(3) || m["tls_psk"] != psk || m["tls_psk_identity"] != "PSK h1" {
+		t.Errorf("Bad payload: %#v", m)
+	}
+
+	hosts[0].TLSPSK = "abc"
+	if err := api.HostsCreate(hosts); !IsInvalidParams(err) {
+		t.Errorf("Expected invalid params error, got %v", err)
+	}
+	hosts[0].TLSPSK = ""
+	hosts[0].TLSConnect = 3
+	if err := api.HostsCreate(hosts); !IsInvalidParams(err) {
+		t.Errorf("Expected invalid params error, got %v", err)
+	}
+
+	hosts = Hosts{{HostId: "1", Host: "h1", TLSPSKIdentity: "PSK h1"}, {HostId: "2", Host: "h2", TLSConnect: TLSPSK}}
+	err := api.HostsRotatePSK(hosts, func(host *Host) string {
+		if host.TLSPSKIdentity != "" {
+			return host.TLSPSKIdentity
+		}
+		return "PSK " + host.Host
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payload) != 2 || payload[1]["tls_psk_identity"] != "PSK h2" || payload[1]["tls_connect"] != float64(2) {
+		t.Errorf("Bad payload: %#v", payload)
+	}
+	if _, present := payload[0]["tls_connect"]; present {
+		t.Errorf("Unexpected tls_connect in payload: %#v", payload[0])
+	}
+	if hosts[0].TLSPSK == hosts[1].TLSPSK || payload[0]["tls_psk"] != hosts[0].TLSPSK || ValidatePSK(hosts[1].TLSPSKIdentity, hosts[1].TLSPSK) != nil {
+		t.Errorf("Bad hosts: %#v", hosts)
+	}
+
+	version = "2.4.0"
+	api = NewAPI(srv.URL)
+	if err := api.ProxiesCreate(Proxies{{Name: "p", TLSConnect: TLSPSK}}); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+}
+
+func TestHostTLS(t *testing.T) {
+	api := getAPI(t)
+	v, err := api.ServerVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.AtLeast(3, 0) {
+		t.Skipf("TLS settings are not supported by Zabbix %s", v)
+	}
+
+	group := CreateHostGroup(t)
+	defer DeleteHostGroup(group, t)
+
+	host := CreateHost(group, t)
+	defer DeleteHost(host, t)
+
+	psk, err := GeneratePSK()
+	if err != nil {
+		t.Fatal(err)
+	}
+	host.GroupIds = nil
+	host.Interfaces = nil
+	host.TLSConnect = TLSPSK
+	host.TLSAccept = TLSPSK | TLSNoEncryption
+	host.TLSPSKIdentity = "PSK " + host.Host
+	host.TLSPSK = psk
+	if err = api.HostsUpdate(Hosts{*host}); err != nil {
+		t.Fatal(err)
+	}
+
+	host2, err := api.HostGetById(host.HostId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host2.TLSConnect != TLSPSK || host2.TLSAccept != TLSPSK|TLSNoEncryption {
+		t.Errorf("TLS settings are not updated: %#v", host2)
+	}
+}
@@ -0,0 +1,163 @@
+package zabbix
+
+type (
+	TriggerSeverity int
+	TriggerStatus   int
+	TriggerValue    int
+)
+
+const (
+	SeverityNotClassified TriggerSeverity = 0
+	SeverityInformation   TriggerSeverity = 1
+	SeverityWarning       TriggerSeverity = 2
+	SeverityAverage       TriggerSeverity = 3
+	SeverityHigh          TriggerSeverity = 4
+	SeverityDisaster      TriggerSeverity = 5
+
+	TriggerEnabled  TriggerStatus = 0
+	TriggerDisabled TriggerStatus = 1
+
+	TriggerOK      TriggerValue = 0
+	TriggerProblem TriggerValue = 1
+)
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/trigger/object
+//
+// Expression syntax depends on server version: "{host:key.func()}>0" before Zabbix 5.4,
+// "func(/host/key)>0" since. TriggersGet returns expressions in this form unless "expandExpression"
+// param is set to false; server returns "{functionid}>0" then. Value and Error are read-only.
+type Trigger struct {
+	TriggerId   string          `json:"triggerid,omitempty"`
+	Description string          `json:"description"`
+	Expression  string          `json:"expression"`
+	Priority    TriggerSeverity `json:"priority"`
+	Status      TriggerStatus   `json:"status"`
+	Comments    string          `json:"comments,omitempty"`
+	URL         string          `json:"url,omitempty"`
+	Value       TriggerValue    `json:"value"`
+	Error       string          `json:"error"`
+	TemplateId  string          `json:"templateid,omitempty"`
+
+	// Filled by TriggersGet with "selectTags" param; supported since Zabbix 3.2
+	Tags Tags `json:"tags,omitempty"`
+}
+
+type Triggers []Trigger
+
+// Wrapper for trigger.get: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/get
+func (api *API) TriggersGet(params Params) (res Triggers, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["expandExpression"]; !present {
+		params["expandExpression"] = true
+	}
+	response, err := api.CallWithError("trigger.get", params)
+	if err != nil {
+		return
+	}
+
+	mapsToStructs(response.Result.([]interface{}), &res)
+	return
+}
+
+// Gets trigger by Id only if there is exactly 1 matching trigger.
+func (api *API) TriggerGetById(id string) (res *Trigger, err error) {
+	triggers, err := api.TriggersGet(Params{"triggerids": id})
+	if err != nil {
+		return
+	}
+
+	if len(triggers) == 1 {
+		res = &triggers[0]
+	} else {
+		e := ExpectedOneResult(len(triggers))
+		err = &e
+	}
+	return
+}
+
+// Gets triggers by host Id.
+func (api *API) TriggersGetByHostId(id string) (res Triggers, err error) {
+	return api.TriggersGet(Params{"hostids": id})
+}
+
+// Converts triggers to payload for trigger.create and trigger.update according to server version.
+// Version is detected only if triggers use fields which depend on it.
+func (api *API) triggersPayload(triggers Triggers) (res []map[string]interface{}, err error) {
+	for _, trigger := range triggers {
+		if len(trigger.Tags) > 0 {
+			var v ServerVersion
+			v, err = api.ServerVersion()
+			if err == nil && !v.AtLeast(3, 2) {
+				err = &UnsupportedError{"Trigger Tags", v}
+			}
+			if err != nil {
+				return
+			}
+			break
+		}
+	}
+	return toMaps(triggers, "value", "error", "templateid")
+}
+
+// Wrapper for trigger.create: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/create
+func (api *API) TriggersCreate(triggers Triggers) (err error) {
+	payload, err := api.triggersPayload(triggers)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("trigger.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	triggerids := result["triggerids"].([]interface{})
+	for i, id := range triggerids {
+		triggers[i].TriggerId = id.(string)
+	}
+	return
+}
+
+// Wrapper for trigger.update: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/update
+func (api *API) TriggersUpdate(triggers Triggers) (err error) {
+	payload, err := api.triggersPayload(triggers)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("trigger.update", payload)
+	return
+}
+
+// Wrapper for trigger.delete: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/delete
+// Cleans TriggerId in all triggers elements if call succeed.
+func (api *API) TriggersDelete(triggers Triggers) (err error) {
+	ids := make([]string, len(triggers))
+	for i, trigger := range triggers {
+		ids[i] = trigger.TriggerId
+	}
+
+	err = api.TriggersDeleteByIds(ids)
+	if err == nil {
+		for i := range triggers {
+			triggers[i].TriggerId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for trigger.delete: https://www.zabbix.com/documentation/current/manual/api/reference/trigger/delete
+func (api *API) TriggersDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("trigger.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	triggerids := result["triggerids"].([]interface{})
+	if len(ids) != len(triggerids) {
+		err = &ExpectedMore{len(ids), len(triggerids)}
+	}
+	return
+}
@@ -0,0 +1,38 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"testing"
+)
+
+func TestTriggersGet(t *testing.T) {
+	var getParams map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		getParams = nil
+		json.Unmarshal(params, &getParams)
+		expression := "{13083}>0"
+		if getParams["expandExpression"] == true {
+			expression = "{host:agent.ping.last()}>0"
+		}
+		return []map[string]string{{"triggerid": "1", "description": "Ping", "expression": expression}}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	triggers, err := api.TriggersGetByHostId("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if triggers[0].Expression != "{host:agent.ping.last()}>0" {
+		t.Errorf("Bad trigger: %#v", triggers[0])
+	}
+
+	triggers, err = api.TriggersGet(Params{"expandExpression": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if triggers[0].Expression != "{13083}>0" {
+		t.Errorf("Bad trigger: %#v", triggers[0])
+	}
+}
@@ -0,0 +1,307 @@
+package zabbix
+
+type (
+	UserType    int
+	MediaStatus int
+)
+
+const (
+	UserTypeUser       UserType = 1
+	UserTypeAdmin      UserType = 2
+	UserTypeSuperAdmin UserType = 3
+
+	MediaEnabled  MediaStatus = 0
+	MediaDisabled MediaStatus = 1
+)
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/user/object#media
+//
+// SendTo with single value is sent as string, with several values (e-mail media types since Zabbix 3.4) as array.
+type Media struct {
+	MediaId     string      `json:"mediaid,omitempty"`
+	UserId      string      `json:"userid,omitempty"`
+	MediaTypeId string      `json:"mediatypeid"`
+	SendTo      []string    `json:"sendto"`
+	Active      MediaStatus `json:"active"`
+	Severity    int         `json:"severity"` // bitmask, 63 for all severities
+	Period      string      `json:"period,omitempty"`
+}
+
+type Medias []Media
+
+type UserGroupId struct {
+	UserGroupId string `json:"usrgrpid"`
+}
+
+type UserGroupIds []UserGroupId
+
+type UserId struct {
+	UserId string `json:"userid"`
+}
+
+type UserIds []UserId
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/user/object
+//
+// Username is sent as alias before Zabbix 5.4. RoleId is supported since Zabbix 5.2, Type before it.
+type User struct {
+	UserId      string   `json:"userid,omitempty"`
+	Username    string   `json:"username"`
+	Name        string   `json:"name"`
+	Surname     string   `json:"surname"`
+	Password    string   `json:"passwd,omitempty"` // write-only
+	URL         string   `json:"url"`
+	AutoLogin   int      `json:"autologin"`
+	AutoLogout  string   `json:"autologout,omitempty"`
+	Lang        string   `json:"lang,omitempty"`
+	Refresh     string   `json:"refresh,omitempty"`
+	Theme       string   `json:"theme,omitempty"`
+	RowsPerPage int      `json:"rows_per_page,omitempty"`
+	RoleId      string   `json:"roleid,omitempty"`
+	Type        UserType `json:"type,omitempty"`
+
+	// Fields below used only when creating and updating users
+	UserGroupIds UserGroupIds `json:"usrgrps,omitempty"`
+
+	// Filled by UsersGet
+	Medias Medias `json:"medias,omitempty"`
+}
+
+type Users []User
+
+// Converts single sendto values of medias in user map to arrays, so they can be decoded into Media.SendTo.
+func normalizeMedias(m map[string]interface{}) {
+	maps, _ := m["medias"].([]interface{})
+	for _, mm := range maps {
+		if mm, ok := mm.(map[string]interface{}); ok {
+			if v, ok := mm["sendto"].(string); ok {
+				mm["sendto"] = []interface{}{v}
+			}
+		}
+	}
+}
+
+// Wrapper for user.get: https://www.zabbix.com/documentation/current/manual/api/reference/user/get
+// Medias are selected by default.
+func (api *API) UsersGet(params Params) (res Users, err error) {
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectMedias"]; !present {
+		params["selectMedias"] = "extend"
+	}
+	response, err := api.CallWithError("user.get", params)
+	if err != nil {
+		return
+	}
+
+	maps := response.Result.([]interface{})
+	for _, m := range maps {
+		m, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if alias, present := m["alias"]; present {
+			m["username"] = alias
+		}
+		normalizeMedias(m)
+	}
+	mapsToStructs(maps, &res)
+	return
+}
+
+// Gets user by Id only if there is exactly 1 matching user.
+func (api *API) UserGetById(id string) (res *User, err error) {
+	users, err := api.UsersGet(Params{"userids": id})
+	if err != nil {
+		return
+	}
+
+	if len(users) == 1 {
+		res = &users[0]
+	} else {
+		e := ExpectedOneResult(len(users))
+		err = &e
+	}
+	return
+}
+
+// Gets user by username only if there is exactly 1 matching user.
+func (api *API) UserGetByUsername(username string) (res *User, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	field := "username"
+	if !v.AtLeast(5, 4) {
+		field = "alias"
+	}
+	users, err := api.UsersGet(Params{"filter": map[string]string{field: username}})
+	if err != nil {
+		return
+	}
+
+	if len(users) == 1 {
+		res = &users[0]
+	} else {
+		e := ExpectedOneResult(len(users))
+		err = &e
+	}
+	return
+}
+
+// Converts medias to payload.
+func mediasPayload(medias Medias) (res []map[string]interface{}, err error) {
+	res, err = toMaps(medias, "mediaid", "userid")
+	if err != nil {
+		return
+	}
+	for i, media := range medias {
+		if len(media.SendTo) == 1 {
+			res[i]["sendto"] = media.SendTo[0]
+		}
+	}
+	return
+}
+
+// Converts users to payload for user.create and user.update according to server version.
+func (api *API) usersPayload(users Users, create bool) (res []map[string]interface{}, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	res, err = toMaps(users)
+	if err != nil {
+		return
+	}
+
+	for i, user := range users {
+		m := res[i]
+		if !v.AtLeast(5, 4) {
+			m["alias"] = m["username"]
+			delete(m, "username")
+		}
+		if v.AtLeast(5, 2) && user.Type != 0 {
+			err = &UnsupportedError{"User Type (use RoleId)", v}
+			return
+		}
+		if !v.AtLeast(5, 2) && user.RoleId != "" {
+			err = &UnsupportedError{"User RoleId", v}
+			return
+		}
+
+		delete(m, "medias")
+		if user.Medias != nil {
+			var medias []map[string]interface{}
+			medias, err = mediasPayload(user.Medias)
+			if err != nil {
+				return
+			}
+			switch {
+			case v.AtLeast(5, 2):
+				m["medias"] = medias
+			case create || v.AtLeast(3, 4):
+				m["user_medias"] = medias
+			default:
+				err = &UnsupportedError{"User Medias on update (use UserMediasUpdate)", v}
+				return
+			}
+		}
+	}
+	return
+}
+
+// Wrapper for user.create: https://www.zabbix.com/documentation/current/manual/api/reference/user/create
+func (api *API) UsersCreate(users Users) (err error) {
+	payload, err := api.usersPayload(users, true)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("user.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	userids := result["userids"].([]interface{})
+	for i, id := range userids {
+		users[i].UserId = id.(string)
+	}
+	return
+}
+
+// Wrapper for user.update: https://www.zabbix.com/documentation/current/manual/api/reference/user/update
+// Groups and medias are replaced if set. Medias can't be updated this way before Zabbix 3.4.
+func (api *API) UsersUpdate(users Users) (err error) {
+	payload, err := api.usersPayload(users, false)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("user.update", payload)
+	return
+}
+
+// Replaces medias of given users, leaving other fields intact.
+// Uses user.updatemedia before Zabbix 3.4 and user.update since.
+func (api *API) UserMediasUpdate(userIds []string, medias Medias) (err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	payload, err := mediasPayload(medias)
+	if err != nil {
+		return
+	}
+
+	if !v.AtLeast(3, 4) {
+		ids := make([]map[string]string, len(userIds))
+		for i, id := range userIds {
+			ids[i] = map[string]string{"userid": id}
+		}
+		_, err = api.CallWithError("user.updatemedia", Params{"users": ids, "medias": payload})
+		return
+	}
+
+	field := "user_medias"
+	if v.AtLeast(5, 2) {
+		field = "medias"
+	}
+	users := make([]map[string]interface{}, len(userIds))
+	for i, id := range userIds {
+		users[i] = map[string]interface{}{"userid": id, field: payload}
+	}
+	_, err = api.CallWithError("user.update", users)
+	return
+}
+
+// Wrapper for user.delete: https://www.zabbix.com/documentation/current/manual/api/reference/user/delete
+// Cleans UserId in all users elements if call succeed.
+func (api *API) UsersDelete(users Users) (err error) {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.UserId
+	}
+
+	err = api.UsersDeleteByIds(ids)
+	if err == nil {
+		for i := range users {
+			users[i].UserId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for user.delete: https://www.zabbix.com/documentation/current/manual/api/reference/user/delete
+func (api *API) UsersDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("user.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	userids := result["userids"].([]interface{})
+	if len(ids) != len(userids) {
+		err = &ExpectedMore{len(ids), len(userids)}
+	}
+	return
+}
@@ -0,0 +1,231 @@
+package zabbix
+
+type (
+	PermissionType int
+	GuiAccessType  int
+	UsersStatus    int
+)
+
+const (
+	PermissionDenied    PermissionType = 0
+	PermissionRead      PermissionType = 2
+	PermissionReadWrite PermissionType = 3
+
+	GuiAccessDefault  GuiAccessType = 0
+	GuiAccessInternal GuiAccessType = 1
+	GuiAccessLDAP     GuiAccessType = 2
+	GuiAccessDisabled GuiAccessType = 3
+
+	UsersEnabled  UsersStatus = 0
+	UsersDisabled UsersStatus = 1
+)
+
+// Permission to host group (Id is host group Id).
+type Permission struct {
+	Id         string         `json:"id"`
+	Permission PermissionType `json:"permission"`
+}
+
+type Permissions []Permission
+
+// Restricts access to problems of host group by tag name and value. Available since Zabbix 4.0.
+type TagFilter struct {
+	GroupId string `json:"groupid"`
+	Tag     string `json:"tag"`
+	Value   string `json:"value"`
+}
+
+type TagFilters []TagFilter
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/usergroup/object
+//
+// Rights are host group permissions, sent as hostgroup_rights since Zabbix 6.2.
+type UserGroup struct {
+	UserGroupId string        `json:"usrgrpid,omitempty"`
+	Name        string        `json:"name"`
+	GuiAccess   GuiAccessType `json:"gui_access"`
+	UsersStatus UsersStatus   `json:"users_status"`
+	DebugMode   int           `json:"debug_mode"`
+
+	// Filled by UserGroupsGet
+	Rights     Permissions `json:"rights,omitempty"`
+	TagFilters TagFilters  `json:"tag_filters,omitempty"`
+	UserIds    []string    `json:"userids,omitempty"`
+}
+
+type UserGroups []UserGroup
+
+// Wrapper for usergroup.get: https://www.zabbix.com/documentation/current/manual/api/reference/usergroup/get
+// Rights, tag filters and users are selected by default.
+func (api *API) UserGroupsGet(params Params) (res UserGroups, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	rightsField := "rights"
+	if v.AtLeast(6, 2) {
+		rightsField = "hostgroup_rights"
+	}
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	defaults := map[string]bool{"selectRights": !v.AtLeast(6, 2), "selectHostGroupRights": v.AtLeast(6, 2),
+		"selectTagFilters": v.AtLeast(4, 0), "selectUsers": true}
+	for p, ok := range defaults {
+		if _, present := params[p]; !present && ok {
+			params[p] = "extend"
+		}
+	}
+	response, err := api.CallWithError("usergroup.get", params)
+	if err != nil {
+		return
+	}
+
+	maps := response.Result.([]interface{})
+	for _, m := range maps {
+		m, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		m["rights"] = m[rightsField]
+		var userIds []interface{}
+		users, _ := m["users"].([]interface{})
+		for _, u := range users {
+			if u, ok := u.(map[string]interface{}); ok {
+				if id, ok := u["userid"].(string); ok {
+					userIds = append(userIds, id)
+				}
+			}
+		}
+		m["userids"] = userIds
+	}
+	mapsToStructs(maps, &res)
+	return
+}
+
+// Gets user group by Id only if there is exactly 1 matching user group.
+func (api *API) UserGroupGetById(id string) (res *UserGroup, err error) {
+	groups, err := api.UserGroupsGet(Params{"usrgrpids": id})
+	if err != nil {
+		return
+	}
+
+	if len(groups) == 1 {
+		res = &groups[0]
+	} else {
+		e := ExpectedOneResult(len(groups))
+		err = &e
+	}
+	return
+}
+
+// Gets user group by name only if there is exactly 1 matching user group.
+func (api *API) UserGroupGetByName(name string) (res *UserGroup, err error) {
+	groups, err := api.UserGroupsGet(Params{"filter": map[string]string{"name": name}})
+	if err != nil {
+		return
+	}
+
+	if len(groups) == 1 {
+		res = &groups[0]
+	} else {
+		e := ExpectedOneResult(len(groups))
+		err = &e
+	}
+	return
+}
+
+// Converts user groups to payload for usergroup.create and usergroup.update according to server version.
+func (api *API) userGroupsPayload(groups UserGroups) (res []map[string]interface{}, err error) {
+	v, err := api.ServerVersion()
+	if err != nil {
+		return
+	}
+	res, err = toMaps(groups)
+	if err != nil {
+		return
+	}
+
+	for i, group := range groups {
+		m := res[i]
+		if len(group.TagFilters) > 0 && !v.AtLeast(4, 0) {
+			err = &UnsupportedError{"UserGroup TagFilters", v}
+			return
+		}
+		if rights, present := m["rights"]; present && v.AtLeast(6, 2) {
+			m["hostgroup_rights"] = rights
+			delete(m, "rights")
+		}
+		if group.UserIds != nil && v.AtLeast(6, 0) {
+			users := make([]map[string]string, len(group.UserIds))
+			for j, id := range group.UserIds {
+				users[j] = map[string]string{"userid": id}
+			}
+			m["users"] = users
+			delete(m, "userids")
+		}
+	}
+	return
+}
+
+// Wrapper for usergroup.create: https://www.zabbix.com/documentation/current/manual/api/reference/usergroup/create
+func (api *API) UserGroupsCreate(groups UserGroups) (err error) {
+	payload, err := api.userGroupsPayload(groups)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("usergroup.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	usrgrpids := result["usrgrpids"].([]interface{})
+	for i, id := range usrgrpids {
+		groups[i].UserGroupId = id.(string)
+	}
+	return
+}
+
+// Wrapper for usergroup.update: https://www.zabbix.com/documentation/current/manual/api/reference/usergroup/update
+// Rights, tag filters and users are replaced if set.
+func (api *API) UserGroupsUpdate(groups UserGroups) (err error) {
+	payload, err := api.userGroupsPayload(groups)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("usergroup.update", payload)
+	return
+}
+
+// Wrapper for usergroup.delete: https://www.zabbix.com/documentation/current/manual/api/reference/usergroup/delete
+// Cleans UserGroupId in all groups elements if call succeed.
+func (api *API) UserGroupsDelete(groups UserGroups) (err error) {
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.UserGroupId
+	}
+
+	err = api.UserGroupsDeleteByIds(ids)
+	if err == nil {
+		for i := range groups {
+			groups[i].UserGroupId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for usergroup.delete: https://www.zabbix.com/documentation/current/manual/api/reference/usergroup/delete
+func (api *API) UserGroupsDeleteByIds(ids []string) (err error) {
+	response, err := api.CallWithError("usergroup.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	usrgrpids := result["usrgrpids"].([]interface{})
+	if len(ids) != len(usrgrpids) {
+		err = &ExpectedMore{len(ids), len(usrgrpids)}
+	}
+	return
+}
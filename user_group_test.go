@@ -0,0 +1,71 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUserGroupsPayload(t *testing.T) {
+	version := "6.2.0"
+	var payload []map[string]interface{}
+	var getParams map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "usergroup.create":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			return map[string]interface{}{"usrgrpids": []string{"9"}}, nil
+		case "usergroup.get":
+			getParams = nil
+			json.Unmarshal(params, &getParams)
+			return []map[string]interface{}{{
+				"usrgrpid": "9", "name": "ops", "gui_access": "1",
+				"hostgroup_rights": []map[string]string{{"id": "2", "permission": "3"}},
+				"tag_filters":      []map[string]string{{"groupid": "2", "tag": "service", "value": "db"}},
+				"users":            []interface{}{map[string]string{"userid": "5"}, map[string]int{"userid": 6}, "7"},
+			}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	groups := UserGroups{{
+		Name:       "ops",
+		Rights:     Permissions{{"2", PermissionReadWrite}},
+		TagFilters: TagFilters{{"2", "service", "db"}},
+		UserIds:    []string{"5"},
+	}}
+	if err := api.UserGroupsCreate(groups); err != nil {
+		t.Fatal(err)
+	}
+	if groups[0].UserGroupId != "9" {
+		t.Errorf("Bad id: %#v", groups[0])
+	}
+	m := payload[0]
+	if m["hostgroup_rights"] == nil || m["rights"] != nil || m["users"] == nil || m["userids"] != nil {
+		t.Errorf("Bad payload: %#v", m)
+	}
+
+	group, err := api.UserGroupGetById("9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if getParams["selectHostGroupRights"] != "extend" || getParams["selectRights"] != nil {
+		t.Errorf("Bad get params: %#v", getParams)
+	}
+	groups[0].GuiAccess = GuiAccessInternal
+	if !reflect.DeepEqual(*group, groups[0]) {
+		t.Errorf("User groups are not equal:\n%#v\n%#v", *group, groups[0])
+	}
+
+	version = "3.4.0"
+	api = NewAPI(srv.URL)
+	if err = api.UserGroupsCreate(groups); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+}
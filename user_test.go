@@ -0,0 +1,113 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUsersPayload(t *testing.T) {
+	version := "5.0.0"
+	var calls []string
+	var payload []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		calls = append(calls, method)
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "user.create":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			return map[string]interface{}{"userids": []string{"5"}}, nil
+		case "user.get":
+			return []map[string]interface{}{{"userid": "5", "alias": "jdoe", "type": "2",
+				"medias": []map[string]interface{}{{"mediaid": "1", "mediatypeid": "1", "sendto": []string{"a@b", "c@d"}, "severity": "63"}},
+			}}, nil
+		case "user.logout":
+			return true, nil
+		case "user.checkAuthentication":
+			if auth != "" {
+				t.Errorf("Unexpected auth for %s", method)
+			}
+			if strings.Contains(string(params), "bad") {
+				return []interface{}{}, nil
+			}
+			return map[string]interface{}{"userid": "5", "username": "jdoe", "sessionid": "abc"}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	api.SetAuth("token")
+	users := Users{{
+		Username:     "jdoe",
+		Type:         UserTypeAdmin,
+		UserGroupIds: UserGroupIds{{"7"}},
+		Medias:       Medias{{MediaTypeId: "1", SendTo: []string{"jdoe@example.com"}, Severity: 63}},
+	}}
+	if err := api.UsersCreate(users); err != nil {
+		t.Fatal(err)
+	}
+	m := payload[0]
+	if m["alias"] != "jdoe" || m["username"] != nil || m["medias"] != nil {
+		t.Errorf("Bad payload: %#v", m)
+	}
+	medias := m["user_medias"].([]interface{})
+	if medias[0].(map[string]interface{})["sendto"] != "jdoe@example.com" {
+		t.Errorf("Bad medias payload: %#v", medias)
+	}
+
+	calls = nil
+	user, err := api.UserGetById("5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(calls, []string{"user.get"}) {
+		t.Errorf("Expected cached version to be used, got calls %v", calls)
+	}
+	if user.Username != "jdoe" || user.Type != UserTypeAdmin || len(user.Medias) != 1 ||
+		!reflect.DeepEqual(user.Medias[0].SendTo, []string{"a@b", "c@d"}) || user.Medias[0].Severity != 63 {
+		t.Errorf("Bad user: %#v", user)
+	}
+
+	version = "6.0.0"
+	api = NewAPI(srv.URL)
+	calls = nil
+	if err = api.UsersCreate(users); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"APIInfo.version"}) {
+		t.Errorf("Expected only version detection before unsupported error, got calls %v", calls)
+	}
+	users[0].Type = 0
+	users[0].RoleId = "3"
+	if err = api.UsersCreate(users); err != nil {
+		t.Fatal(err)
+	}
+	m = payload[0]
+	if m["username"] != "jdoe" || m["roleid"] != "3" || m["medias"] == nil || m["user_medias"] != nil {
+		t.Errorf("Bad payload: %#v", m)
+	}
+
+	user, err = api.CheckAuthentication("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.UserId != "5" || user.Username != "jdoe" {
+		t.Errorf("Bad user: %#v", user)
+	}
+	if user, err = api.CheckAuthentication("bad"); err == nil || user != nil {
+		t.Errorf("Expected error, got %v, %#v", err, user)
+	}
+
+	api.SetAuth("token")
+	if err = api.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	if api.Auth() != "" {
+		t.Errorf("Auth is not cleared: %s", api.Auth())
+	}
+}
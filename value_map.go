@@ -0,0 +1,282 @@
+package zabbix
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type (
+	ValueMappingType int
+)
+
+const (
+	MappingEqual          ValueMappingType = 0
+	MappingGreaterOrEqual ValueMappingType = 1
+	MappingLessOrEqual    ValueMappingType = 2
+	MappingRange          ValueMappingType = 3
+	MappingRegexp         ValueMappingType = 4
+	MappingDefault        ValueMappingType = 5
+)
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/valuemap/object#value-mappings
+//
+// Types other than MappingEqual are supported since Zabbix 6.0.
+type ValueMapping struct {
+	Type     ValueMappingType `json:"type,omitempty"`
+	Value    string           `json:"value"`
+	NewValue string           `json:"newvalue"`
+}
+
+type ValueMappings []ValueMapping
+
+// https://www.zabbix.com/documentation/current/manual/api/reference/valuemap/object
+//
+// Value maps are available via API since Zabbix 3.0. They are global before Zabbix 5.4
+// and belong to host or template since 5.4, where HostId is required.
+type ValueMap struct {
+	ValueMapId string        `json:"valuemapid,omitempty"`
+	HostId     string        `json:"hostid,omitempty"`
+	Name       string        `json:"name"`
+	Mappings   ValueMappings `json:"mappings,omitempty"`
+}
+
+type ValueMaps []ValueMap
+
+// Maps value as Zabbix does: MappingEqual mappings are checked first, then other mappings in order,
+// then MappingDefault. Numeric values are compared as numbers.
+func (valueMap *ValueMap) Map(value string) (newValue string, ok bool) {
+	if valueMap == nil {
+		return
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	numeric := err == nil
+
+	for _, m := range valueMap.Mappings {
+		if m.Type != MappingEqual {
+			continue
+		}
+		if m.Value == value {
+			return m.NewValue, true
+		}
+		if n, err := strconv.ParseFloat(m.Value, 64); numeric && err == nil && n == number {
+			return m.NewValue, true
+		}
+	}
+
+	for _, m := range valueMap.Mappings {
+		matched := false
+		switch m.Type {
+		case MappingGreaterOrEqual:
+			n, err := strconv.ParseFloat(m.Value, 64)
+			matched = numeric && err == nil && number >= n
+		case MappingLessOrEqual:
+			n, err := strconv.ParseFloat(m.Value, 64)
+			matched = numeric && err == nil && number <= n
+		case MappingRange:
+			matched = numeric && inRanges(number, m.Value)
+		case MappingRegexp:
+			re, err := regexp.Compile(m.Value)
+			matched = err == nil && re.MatchString(value)
+		}
+		if matched {
+			return m.NewValue, true
+		}
+	}
+
+	for _, m := range valueMap.Mappings {
+		if m.Type == MappingDefault {
+			return m.NewValue, true
+		}
+	}
+	return
+}
+
+// Renders value like Zabbix frontend: "newvalue (value)" if value is mapped, value as is otherwise.
+func (valueMap *ValueMap) Render(value string) string {
+	if newValue, ok := valueMap.Map(value); ok {
+		return newValue + " (" + value + ")"
+	}
+	return value
+}
+
+// Reports whether number is within comma-separated list of numbers and ranges like "1-10,20,-5--1".
+func inRanges(number float64, ranges string) bool {
+	for _, r := range strings.Split(ranges, ",") {
+		r = strings.TrimSpace(r)
+		from, to := r, r
+		if len(r) > 1 {
+			// skip sign of the first number
+			if i := strings.Index(r[1:], "-"); i >= 0 {
+				from, to = r[:i+1], r[i+2:]
+			}
+		}
+		f, err1 := strconv.ParseFloat(strings.TrimSpace(from), 64)
+		t, err2 := strconv.ParseFloat(strings.TrimSpace(to), 64)
+		if err1 == nil && err2 == nil && f <= number && number <= t {
+			return true
+		}
+	}
+	return false
+}
+
+func (api *API) checkValueMaps() (v ServerVersion, err error) {
+	v, err = api.ServerVersion()
+	if err == nil && !v.AtLeast(3, 0) {
+		err = &UnsupportedError{"Value maps", v}
+	}
+	return
+}
+
+// Wrapper for valuemap.get: https://www.zabbix.com/documentation/current/manual/api/reference/valuemap/get
+// Mappings are selected by default.
+func (api *API) ValueMapsGet(params Params) (res ValueMaps, err error) {
+	_, err = api.checkValueMaps()
+	if err != nil {
+		return
+	}
+	if _, present := params["output"]; !present {
+		params["output"] = "extend"
+	}
+	if _, present := params["selectMappings"]; !present {
+		params["selectMappings"] = "extend"
+	}
+	response, err := api.CallWithError("valuemap.get", params)
+	if err != nil {
+		return
+	}
+
+	mapsToStructs(response.Result.([]interface{}), &res)
+	return
+}
+
+// Gets value map by Id only if there is exactly 1 matching value map.
+func (api *API) ValueMapGetById(id string) (res *ValueMap, err error) {
+	valueMaps, err := api.ValueMapsGet(Params{"valuemapids": id})
+	if err != nil {
+		return
+	}
+
+	if len(valueMaps) == 1 {
+		res = &valueMaps[0]
+	} else {
+		e := ExpectedOneResult(len(valueMaps))
+		err = &e
+	}
+	return
+}
+
+// Gets value maps by host Id. Supported since Zabbix 5.4.
+func (api *API) ValueMapsGetByHostId(id string) (res ValueMaps, err error) {
+	v, err := api.checkValueMaps()
+	if err == nil && !v.AtLeast(5, 4) {
+		err = &UnsupportedError{"Host value maps", v}
+	}
+	if err != nil {
+		return
+	}
+	return api.ValueMapsGet(Params{"hostids": id})
+}
+
+// Converts value maps to payload for valuemap.create and valuemap.update according to server version.
+func (api *API) valueMapsPayload(valueMaps ValueMaps, create bool) (res []map[string]interface{}, err error) {
+	v, err := api.checkValueMaps()
+	if err != nil {
+		return
+	}
+	res, err = toMaps(valueMaps)
+	if err != nil {
+		return
+	}
+
+	for i, vm := range valueMaps {
+		m := res[i]
+		if v.AtLeast(5, 4) {
+			if create && vm.HostId == "" {
+				err = &UnsupportedError{"Global value maps", v}
+				return
+			}
+			if !create {
+				delete(m, "hostid")
+			}
+		} else if vm.HostId != "" {
+			err = &UnsupportedError{"Value map HostId", v}
+			return
+		}
+		if !v.AtLeast(6, 0) {
+			for _, mapping := range vm.Mappings {
+				if mapping.Type != MappingEqual {
+					err = &UnsupportedError{"Value mapping types", v}
+					return
+				}
+			}
+		}
+	}
+	return
+}
+
+// Wrapper for valuemap.create: https://www.zabbix.com/documentation/current/manual/api/reference/valuemap/create
+func (api *API) ValueMapsCreate(valueMaps ValueMaps) (err error) {
+	payload, err := api.valueMapsPayload(valueMaps, true)
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("valuemap.create", payload)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	valuemapids := result["valuemapids"].([]interface{})
+	for i, id := range valuemapids {
+		valueMaps[i].ValueMapId = id.(string)
+	}
+	return
+}
+
+// Wrapper for valuemap.update: https://www.zabbix.com/documentation/current/manual/api/reference/valuemap/update
+// Mappings are replaced by valueMap.Mappings.
+func (api *API) ValueMapsUpdate(valueMaps ValueMaps) (err error) {
+	payload, err := api.valueMapsPayload(valueMaps, false)
+	if err != nil {
+		return
+	}
+	_, err = api.CallWithError("valuemap.update", payload)
+	return
+}
+
+// Wrapper for valuemap.delete: https://www.zabbix.com/documentation/current/manual/api/reference/valuemap/delete
+// Cleans ValueMapId in all valueMaps elements if call succeed.
+func (api *API) ValueMapsDelete(valueMaps ValueMaps) (err error) {
+	ids := make([]string, len(valueMaps))
+	for i, vm := range valueMaps {
+		ids[i] = vm.ValueMapId
+	}
+
+	err = api.ValueMapsDeleteByIds(ids)
+	if err == nil {
+		for i := range valueMaps {
+			valueMaps[i].ValueMapId = ""
+		}
+	}
+	return
+}
+
+// Wrapper for valuemap.delete: https://www.zabbix.com/documentation/current/manual/api/reference/valuemap/delete
+func (api *API) ValueMapsDeleteByIds(ids []string) (err error) {
+	_, err = api.checkValueMaps()
+	if err != nil {
+		return
+	}
+	response, err := api.CallWithError("valuemap.delete", ids)
+	if err != nil {
+		return
+	}
+
+	result := response.Result.(map[string]interface{})
+	valuemapids := result["valuemapids"].([]interface{})
+	if len(ids) != len(valuemapids) {
+		err = &ExpectedMore{len(ids), len(valuemapids)}
+	}
+	return
+}
@@ -0,0 +1,112 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestValueMapRender(t *testing.T) {
+	vm := &ValueMap{Name: "Service state", Mappings: ValueMappings{
+		{Type: MappingDefault, NewValue: "Unknown"},
+		{Type: MappingRange, Value: "-10--1,100-200", NewValue: "Error"},
+		{Value: "0", NewValue: "Down"},
+		{Type: MappingGreaterOrEqual, Value: "1", NewValue: "Up"},
+		{Type: MappingRegexp, Value: "^warn", NewValue: "Warning"},
+		{Value: "150", NewValue: "Special"},
+	}}
+	for value, expected := range map[string]string{
+		"0":       "Down (0)",
+		"0.0":     "Down (0.0)",
+		"150":     "Special (150)",
+		"-5":      "Error (-5)",
+		"120":     "Error (120)",
+		"7":       "Up (7)",
+		"warning": "Warning (warning)",
+		"-20":     "Unknown (-20)",
+	} {
+		if actual := vm.Render(value); actual != expected {
+			t.Errorf("%q: expected %q, got %q", value, expected, actual)
+		}
+	}
+
+	var nilMap *ValueMap
+	history := History{{Value: "0"}, {Value: "3"}}
+	if r := history.Render(nilMap); !reflect.DeepEqual(r, []string{"0", "3"}) {
+		t.Errorf("Bad render: %#v", r)
+	}
+}
+
+func TestValueMaps(t *testing.T) {
+	version := "5.0.0"
+	var payload []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "valuemap.create":
+			payload = nil
+			json.Unmarshal(params, &payload)
+			return map[string]interface{}{"valuemapids": []string{"5"}}, nil
+		case "valuemap.get":
+			return []map[string]interface{}{{"valuemapid": "5", "name": "State",
+				"mappings": []map[string]string{{"value": "0", "newvalue": "Down"}, {"value": "1", "newvalue": "Up"}}}}, nil
+		case "item.get":
+			return []map[string]string{{"itemid": "1", "valuemapid": "5", "value_type": "3"}, {"itemid": "2", "valuemapid": "0"}}, nil
+		case "history.get":
+			return []map[string]string{{"itemid": "1", "clock": "1600000000", "ns": "0", "value": "1"}}, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	vms := ValueMaps{{Name: "State", Mappings: ValueMappings{{Value: "0", NewValue: "Down"}, {Value: "1", NewValue: "Up"}}}}
+	if err := api.ValueMapsCreate(vms); err != nil {
+		t.Fatal(err)
+	}
+	if vms[0].ValueMapId != "5" || payload[0]["hostid"] != nil {
+		t.Errorf("Bad payload: %#v", payload[0])
+	}
+	vm, err := api.ValueMapGetById("5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*vm, vms[0]) {
+		t.Errorf("Value maps are not equal:\n%#v\n%#v", *vm, vms[0])
+	}
+
+	items, err := api.ItemsGet(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].ValueMapId != "5" || items[1].ValueMapId != "" {
+		t.Errorf("Bad items: %#v", items)
+	}
+	history, err := api.HistoryGetByItem(&items[0], Params{"limit": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := history.Render(vm); !reflect.DeepEqual(r, []string{"Up (1)"}) || history[0].Clock != 1600000000 {
+		t.Errorf("Bad history: %#v %#v", history, r)
+	}
+
+	vms[0].Mappings[0].Type = MappingDefault
+	if err = api.ValueMapsCreate(vms); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+
+	version = "6.0.0"
+	api = NewAPI(srv.URL)
+	if err = api.ValueMapsCreate(vms); !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error for global value map, got %v", err)
+	}
+	vms[0].HostId = "10"
+	if err = api.ValueMapsCreate(vms); err != nil {
+		t.Fatal(err)
+	}
+	if payload[0]["hostid"] != "10" {
+		t.Errorf("Bad payload: %#v", payload[0])
+	}
+}
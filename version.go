@@ -0,0 +1,105 @@
+package zabbix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parsed Zabbix server (API) version. Zero value means unknown version.
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// Parses version like "2.0.8", "6.0.10" or "7.0.0rc1". Suffixes are ignored.
+func ParseServerVersion(s string) (v ServerVersion, err error) {
+	parts := strings.SplitN(s, ".", 3)
+	if len(parts) < 2 {
+		err = fmt.Errorf("Failed to parse Zabbix version %q.", s)
+		return
+	}
+
+	nums := make([]int, 3)
+	for i, p := range parts {
+		end := 0
+		for end < len(p) && p[end] >= '0' && p[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			err = fmt.Errorf("Failed to parse Zabbix version %q.", s)
+			return
+		}
+		nums[i], _ = strconv.Atoi(p[:end])
+	}
+	v = ServerVersion{nums[0], nums[1], nums[2]}
+	return
+}
+
+func (v ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Returns -1, 0 or 1 if v is less than, equal to or greater than other.
+func (v ServerVersion) Compare(other ServerVersion) int {
+	a := [3]int{v.Major, v.Minor, v.Patch}
+	b := [3]int{other.Major, other.Minor, other.Patch}
+	for i := range a {
+		if a[i] < b[i] {
+			return -1
+		}
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Returns true if v is major.minor or later.
+func (v ServerVersion) AtLeast(major, minor int) bool {
+	return v.Compare(ServerVersion{Major: major, Minor: minor}) >= 0
+}
+
+// Returns true if v is unknown (zero value).
+func (v ServerVersion) IsZero() bool {
+	return v == ServerVersion{}
+}
+
+// Calls "APIInfo.version" API method once and caches result.
+func (api *API) Version() (v string, err error) {
+	api.m.RLock()
+	v = api.version
+	api.m.RUnlock()
+	if v != "" {
+		return
+	}
+
+	response, err := api.CallWithError("APIInfo.version", Params{})
+	if err != nil {
+		return
+	}
+
+	v = response.Result.(string)
+	api.m.Lock()
+	api.version = v
+	api.m.Unlock()
+	return
+}
+
+// Returns parsed server version, detecting it with Version() if needed.
+func (api *API) ServerVersion() (v ServerVersion, err error) {
+	s, err := api.Version()
+	if err != nil {
+		return
+	}
+	return ParseServerVersion(s)
+}
+
+// Sets server version, so it is not detected by Version() call. Mostly useful for tests and
+// for setups where version is known in advance.
+func (api *API) SetVersion(v string) {
+	api.m.Lock()
+	api.version = v
+	api.m.Unlock()
+}
@@ -0,0 +1,165 @@
+package zabbix_test
+
+import (
+	. "."
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	for s, expected := range map[string]ServerVersion{
+		"2.0.8":    {2, 0, 8},
+		"6.0.10":   {6, 0, 10},
+		"7.0.0rc1": {7, 0, 0},
+		"5.4":      {5, 4, 0},
+	} {
+		v, err := ParseServerVersion(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != expected {
+			t.Errorf("%s: expected %s, got %s", s, expected, v)
+		}
+	}
+
+	for _, s := range []string{"", "2", "x.y.z"} {
+		if _, err := ParseServerVersion(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+
+	v := ServerVersion{3, 4, 0}
+	if !v.AtLeast(3, 4) || !v.AtLeast(2, 0) || v.AtLeast(5, 4) {
+		t.Errorf("Bad AtLeast for %s", v)
+	}
+	if v.Compare(ServerVersion{3, 4, 1}) != -1 || v.Compare(ServerVersion{3, 2, 9}) != 1 || v.Compare(v) != 0 {
+		t.Errorf("Bad Compare for %s", v)
+	}
+}
+
+func TestVersionAdaptation(t *testing.T) {
+	version := "4.0.0"
+	var created, updated []map[string]interface{}
+	srv := newFakeServer(t, func(method string, params json.RawMessage, auth string) (interface{}, *Error) {
+		switch method {
+		case "APIInfo.version":
+			return version, nil
+		case "item.create":
+			created = nil
+			json.Unmarshal(params, &created)
+			return map[string]interface{}{"itemids": []string{"1"}}, nil
+		case "item.update":
+			updated = nil
+			json.Unmarshal(params, &updated)
+			return map[string]interface{}{"itemids": []string{"1"}}, nil
+		case "item.get":
+			if version == "3.2.0" {
+				return []map[string]string{{"itemid": "1", "hostid": "1", "key_": "key", "delay": "60", "history": "90", "trends": "0"}}, nil
+			}
+			items := []map[string]string{
+				{"itemid": "1", "delay": "1m", "history": "90d", "trends": "365d"},
+				{"itemid": "2", "delay": "{$INTERVAL}", "history": "1h", "trends": "0"},
+				{"itemid": "3", "hostid": "1", "key_": "key", "delay": "30s;50s/1-5,09:00-18:00", "history": "{$HISTORY}", "trends": "0"},
+			}
+			if strings.Contains(string(params), `"filter"`) {
+				items = items[2:]
+			}
+			return items, nil
+		}
+		return []interface{}{}, nil
+	})
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	err := api.ItemsCreate(Items{{HostId: "1", Key: "key", Delay: "60", History: "7d"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := created[0]
+	if m["delay"] != "60" || m["history"] != "7d" {
+		t.Errorf("Bad payload: %#v", m)
+	}
+	for _, f := range []string{"data_type", "delta", "error", "trends"} {
+		if _, present := m[f]; present {
+			t.Errorf("Unexpected %s in payload: %#v", f, m)
+		}
+	}
+
+	items, err := api.ItemsGet(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].Delay != "1m" || items[0].History != "90d" || items[0].Trends != "365d" {
+		t.Errorf("Bad item: %#v", items[0])
+	}
+	if items[1].Delay != "{$INTERVAL}" || items[1].History != "1h" || items[1].Trends != "0" {
+		t.Errorf("Bad item: %#v", items[1])
+	}
+	if items[2].Delay != "30s;50s/1-5,09:00-18:00" || items[2].History != "{$HISTORY}" {
+		t.Errorf("Bad item: %#v", items[2])
+	}
+
+	// read items are written back as is
+	if err = api.ItemsUpdate(items[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if m = updated[0]; m["delay"] != "{$INTERVAL}" || m["history"] != "1h" || m["trends"] != "0" {
+		t.Errorf("Bad payload: %#v", m)
+	}
+	if m = updated[1]; m["delay"] != "30s;50s/1-5,09:00-18:00" || m["history"] != "{$HISTORY}" || m["trends"] != "0" {
+		t.Errorf("Bad payload: %#v", m)
+	}
+
+	// unchanged item with flexible delay is not updated
+	updated = nil
+	item, err := api.ItemEnsure(Item{HostId: "1", Key: "key", Delay: "30s;50s/1-5,09:00-18:00", Trends: "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ItemId != "3" || updated != nil {
+		t.Errorf("Unexpected update: %#v", updated)
+	}
+
+	version = "3.2.0"
+	api = NewAPI(srv.URL)
+	err = api.ItemsCreate(Items{{HostId: "1", Key: "key", Delay: "1m", History: "7d", Trends: "0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m = created[0]; m["delay"] != float64(60) || m["history"] != float64(7) || m["trends"] != float64(0) {
+		t.Errorf("Bad payload: %#v", m)
+	}
+	for _, item := range []Item{{Key: "key", Delay: "{$INTERVAL}"}, {Key: "key", Delay: "30s;50s/1-5,09:00-18:00"}, {Key: "key", History: "12h"}} {
+		if err = api.ItemsCreate(Items{item}); !IsInvalidParams(err) {
+			t.Errorf("%#v: expected invalid params error, got %v", item, err)
+		}
+	}
+	items, err = api.ItemsGet(Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].Delay != "60" || items[0].History != "90d" || items[0].Trends != "0" {
+		t.Errorf("Bad item: %#v", items[0])
+	}
+
+	// "1m" equals "60" returned by server
+	updated = nil
+	if _, err = api.ItemEnsure(Item{HostId: "1", Key: "key", Delay: "1m", History: "90d"}); err != nil {
+		t.Fatal(err)
+	}
+	if updated != nil {
+		t.Errorf("Unexpected update: %#v", updated)
+	}
+
+	version = "5.4.0"
+	api = NewAPI(srv.URL)
+	_, err = api.ApplicationsGet(Params{})
+	if !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+	err = api.ItemsCreate(Items{{HostId: "1", Key: "key", ApplicationIds: []string{"1"}}})
+	if !IsUnsupported(err) {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+}